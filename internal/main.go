@@ -121,13 +121,15 @@ func demoSpinner(ctx *demoContext) {
 	matrixCtx, cancel := context.WithCancel(context.Background())
 	done := m.Start(matrixCtx)
 
+	blocks, _ := termite.SpinnerPresetByName(termite.SpinnerPresetBlocks)
+	line, _ := termite.SpinnerPresetByName(termite.SpinnerPresetLine)
 	customFormatter1 := &customSpinnerFormatter{
-		charSeq:           []string{"\u2588", "\u2587", "\u2586", "\u2585", "\u2584", "\u2583", "\u2582", "\u2581"},
+		charSeq:           blocks.Frames,
 		formatTitleFn:     color.CyanString,
 		formatIndicatorFn: color.RedString,
 	}
 	customFormatter2 := &customSpinnerFormatter{
-		charSeq:           []string{"/", "-", "\\", "|"},
+		charSeq:           line.Frames,
 		formatTitleFn:     color.MagentaString,
 		formatIndicatorFn: color.GreenString,
 	}
@@ -146,7 +148,7 @@ func demoSpinner(ctx *demoContext) {
 	}
 	time.Sleep(time.Second)
 	for _, spinner := range spinners {
-		_ = spinner.Stop(context.Background(), "- Done "+taskDoneMarkUniChar)
+		_ = spinner.Succeed("Done")
 	}
 
 	cancel()
@@ -189,53 +191,39 @@ func demoCursor(ctx *demoContext) {
 func demoConcurrentProgressBars(ctx *demoContext) {
 	printTitle("Concurrent tasks progress", ctx)
 
-	cursor := termite.NewCursor(termite.StdoutWriter)
+	m := termite.NewMatrix(termite.StdoutWriter, progressRefreshInterval)
+	matrixCtx, cancel := context.WithCancel(context.Background())
+	done := m.Start(matrixCtx)
+
 	ticks := 200
-	progressTickerWith := func(width int, formatter termite.ProgressBarFormatter) (func(), context.CancelFunc) {
-		bar := termite.NewProgressBar(termite.StdoutWriter, ticks, ctx.termWidth, width, formatter)
-		c, cancel := context.WithCancel(context.Background())
-		tick, _ := bar.Start(c)
+	progressTickerWith := func(width int, formatter termite.ProgressBarFormatter) func() {
+		bar := termite.NewProgressBar(m.NewRow(), ticks, ctx.termWidth, width, formatter)
+		tick, _ := bar.Start(matrixCtx)
 		actualTicks := 0
 
 		return func() {
 			actualTicks++
 			tick(fmt.Sprintf("Running %d out of %d :", actualTicks, ticks))
-			cursor.Down(1)
-		}, cancel
+		}
 	}
 
-	var cancel1, cancel2, cancel3, cancel4 context.CancelFunc
-	var tick1, tick2, tick3, tick4 func()
-
 	termWidth := ctx.termWidth
-	termite.AllocateNewLines(4) // allocate 4 lines
-	tick1, cancel1 = progressTickerWith(termWidth()*3/16, &customProgressBarFormatter{Fill: '\u258C', formatBorderFn: color.WhiteString, formatFillFn: color.HiCyanString})
-	tick2, cancel2 = progressTickerWith(termWidth()*1/4, &customProgressBarFormatter{Fill: '\u2592', formatBorderFn: color.YellowString, formatFillFn: color.BlueString})
-	tick3, cancel3 = progressTickerWith(termWidth()*3/8, &customProgressBarFormatter{Fill: '\u2591', formatBorderFn: color.GreenString, formatFillFn: color.RedString})
-	tick4, cancel4 = progressTickerWith(termWidth()*1/2, &customProgressBarFormatter{Fill: '\u2587', formatBorderFn: color.RedString, formatFillFn: color.GreenString})
-
-	defer func() {
-		cancel1()
-		cancel2()
-		cancel3()
-		cancel4()
-	}()
-	tick := func() {
+	tick1 := progressTickerWith(termWidth()*3/16, &customProgressBarFormatter{Fill: '\u258C', formatBorderFn: color.WhiteString, formatFillFn: color.HiCyanString})
+	tick2 := progressTickerWith(termWidth()*1/4, &customProgressBarFormatter{Fill: '\u2592', formatBorderFn: color.YellowString, formatFillFn: color.BlueString})
+	tick3 := progressTickerWith(termWidth()*3/8, &customProgressBarFormatter{Fill: '\u2591', formatBorderFn: color.GreenString, formatFillFn: color.RedString})
+	tick4 := progressTickerWith(termWidth()*1/2, &customProgressBarFormatter{Fill: '\u2587', formatBorderFn: color.RedString, formatFillFn: color.GreenString})
+
+	for i := 0; i < ticks; i++ {
 		tick1()
 		tick2()
 		tick3()
 		tick4()
-	}
-
-	for i := 0; i < ticks; i++ {
-		tick()
 		time.Sleep(time.Millisecond * 10)
-		cursor.Up(4)
 	}
-	cursor.Down(4)
-	cursor.Show()
 
-	termite.Println("\n")
+	cancel()
+	<-done
+	termite.Println("")
 }
 
 func printTitle(s string, ctx *demoContext) {
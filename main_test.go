@@ -0,0 +1,15 @@
+package termite
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestMain(m *testing.M) {
+	// expected outputs are uncolored, regardless of whether the tests run in a terminal or not
+	color.NoColor = true
+
+	os.Exit(m.Run())
+}
@@ -10,6 +10,14 @@ import (
 	"time"
 )
 
+const (
+	// DefaultMatrixRefreshInterval the default interval between two updates of a matrix
+	DefaultMatrixRefreshInterval = time.Millisecond * 100
+
+	// maxLineFeedCursorMoves the longest distance the cursor is moved down with line feeds rather than a cursor sequence
+	maxLineFeedCursorMoves = 4
+)
+
 // Matrix is a multiline structure that reflects its state on screen
 type Matrix interface {
 	// Start starts to update this matrix in the background.
@@ -32,7 +40,25 @@ type Matrix interface {
 	// GetRowByID looks up a row an ID. Returns an error if none exists
 	GetRowByID(MatrixCellID) (MatrixRow, error)
 
-	// UpdateTerminal updates the terminal immediately.
+	// IndexOf returns the current index of the row with the specified ID. Returns an error if none exists
+	IndexOf(MatrixCellID) (int, error)
+
+	// InsertRow allocates a row at the specified index, shifting the rows at and after it down.
+	// The index must be in the range of [0, number of rows].
+	InsertRow(index int) (MatrixRow, error)
+
+	// DeleteRow removes the row with the specified ID. The rows after it shift up and the screen lines vacated at
+	// the bottom of the matrix are cleared by the next update. Writing to a deleted row has no effect.
+	DeleteRow(MatrixCellID) error
+
+	// MoveRow moves the row with the specified ID to the specified index, shifting the rows in between.
+	MoveRow(id MatrixCellID, index int) error
+
+	// SwapRows swaps the positions of the rows with the specified IDs.
+	SwapRows(a, b MatrixCellID) error
+
+	// UpdateTerminal updates the terminal immediately. Only the lines that changed since the previous update are
+	// rewritten and nothing is written when nothing changed.
 	//
 	// This function can be used as a manual alternative to Start(), which updates the terminal
 	// in the background based on the interval specified in the constructor. Combining Start with
@@ -40,18 +66,35 @@ type Matrix interface {
 	UpdateTerminal(resetCursorPosition bool)
 }
 
-// MatrixCellID used to identify a Matrix cell internally
+// MatrixBuilder follows the builder pattern for creating a Matrix.
+type MatrixBuilder interface {
+	WithWriter(writer io.Writer) MatrixBuilder
+	WithRefreshInterval(refreshInterval time.Duration) MatrixBuilder
+	// WithTerminalHeightFn sets the function the terminal height is read from on every update. A non-positive height
+	// disables the viewport.
+	WithTerminalHeightFn(terminalHeightFn func() int) MatrixBuilder
+	// WithTerminalWidthFn sets the function the terminal width is read from on every update. Rows wider than the
+	// terminal are fitted to it according to their overflow policy. A non-positive width disables fitting.
+	WithTerminalWidthFn(terminalWidthFn func() int) MatrixBuilder
+	// WithViewportPolicy sets the policy that decides which rows stay visible when the rows don't fit on the terminal.
+	WithViewportPolicy(policy MatrixViewportPolicy) MatrixBuilder
+	Build() Matrix
+}
+
+// MatrixCellID used to identify a Matrix cell internally. An ID remains stable as rows are inserted, deleted and moved.
 type MatrixCellID struct {
 	row int
 }
 
-// Row returns the row index associated with this ID
+// Row returns the row index associated with this ID, which is the index the row was allocated at as long as rows are
+// only ever appended. Use Matrix.IndexOf to get the current index of a row.
 func (id MatrixCellID) Row() int {
 	return id.row
 }
 
 // MatrixRow an accessor to a line in a Matrix structure
-// Line feed and return characters are trimmed from written strings to prevent breaking the layout of the matrix.
+// Leading and trailing line feed and return characters are trimmed from written strings to prevent breaking the layout
+// of the matrix, while line feeds in between split the row into several lines.
 type MatrixRow interface {
 	io.StringWriter
 	io.Writer
@@ -59,27 +102,121 @@ type MatrixRow interface {
 	Update(string)
 }
 
+// ExtendedMatrixRow an extension of MatrixRow for controlling how a row is laid out. All the rows of a Matrix created by
+// this package implement it, so a MatrixRow can be type-asserted.
+type ExtendedMatrixRow interface {
+	MatrixRow
+
+	// SetPinned sets whether or not this row stays visible when the matrix has more rows than fit on the terminal.
+	SetPinned(bool)
+	// SetOverflow sets how this row is fitted to the terminal when it is wider. Defaults to MatrixRowOverflowEllipsis.
+	SetOverflow(MatrixRowOverflow)
+	// SetHidden sets whether or not this row is hidden. A hidden row keeps its place and value, but takes no lines on
+	// screen and is not counted by the viewport.
+	SetHidden(bool)
+	// SetDone sets whether or not the task this row reflects has finished, for viewport policies to take into account.
+	SetDone(bool)
+}
+
 type matrixImpl struct {
 	rows            []*matrixRow
 	refreshInterval time.Duration
 	writer          io.Writer
 	mx              *sync.RWMutex
+	nextID          int
+	screen          []string
+
+	terminalHeightFn func() int
+	terminalWidthFn  func() int
+	viewportPolicy   MatrixViewportPolicy
+	now              func() time.Time
 }
 
 type matrixRow struct {
-	id       MatrixCellID
-	matrix   *matrixImpl
-	value    string
-	modified bool
+	id      MatrixCellID
+	matrix  *matrixImpl
+	value   string
+	deleted bool
+	updated time.Time
+	pinned  bool
+	done    bool
+	hidden  bool
+
+	overflow      MatrixRowOverflow
+	marqueeOffset int
 }
 
 // NewMatrix creates a new matrix that writes to the specified writer and refreshes every refreshInterval.
+// When the rows don't fit on the terminal, only a viewport of them is rendered using MatrixViewportRunningFirst.
 func NewMatrix(writer io.Writer, refreshInterval time.Duration) Matrix {
+	return NewMatrixBuilder().
+		WithWriter(writer).
+		WithRefreshInterval(refreshInterval).
+		Build()
+}
+
+type matrixBuilder struct {
+	writer           io.Writer
+	refreshInterval  time.Duration
+	terminalHeightFn func() int
+	terminalWidthFn  func() int
+	viewportPolicy   MatrixViewportPolicy
+}
+
+// NewMatrixBuilder creates a new MatrixBuilder with default values.
+// By default the matrix writes to Stdout, refreshes every DefaultMatrixRefreshInterval, fits its rows to the width
+// of the terminal and limits them to the height of the terminal using MatrixViewportRunningFirst.
+func NewMatrixBuilder() MatrixBuilder {
+	return &matrixBuilder{
+		writer:          StdoutWriter,
+		refreshInterval: DefaultMatrixRefreshInterval,
+		terminalHeightFn: func() int {
+			_, height, _ := GetTerminalDimensions()
+			return height
+		},
+		terminalWidthFn: func() int {
+			width, _, _ := GetTerminalDimensions()
+			return width
+		},
+		viewportPolicy: MatrixViewportRunningFirst,
+	}
+}
+
+func (mb *matrixBuilder) WithWriter(writer io.Writer) MatrixBuilder {
+	mb.writer = writer
+	return mb
+}
+
+func (mb *matrixBuilder) WithRefreshInterval(refreshInterval time.Duration) MatrixBuilder {
+	mb.refreshInterval = refreshInterval
+	return mb
+}
+
+func (mb *matrixBuilder) WithTerminalHeightFn(terminalHeightFn func() int) MatrixBuilder {
+	mb.terminalHeightFn = terminalHeightFn
+	return mb
+}
+
+func (mb *matrixBuilder) WithTerminalWidthFn(terminalWidthFn func() int) MatrixBuilder {
+	mb.terminalWidthFn = terminalWidthFn
+	return mb
+}
+
+func (mb *matrixBuilder) WithViewportPolicy(policy MatrixViewportPolicy) MatrixBuilder {
+	mb.viewportPolicy = policy
+	return mb
+}
+
+func (mb *matrixBuilder) Build() Matrix {
 	return &matrixImpl{
-		rows:            []*matrixRow{},
-		refreshInterval: refreshInterval,
-		writer:          writer,
-		mx:              &sync.RWMutex{},
+		rows:             []*matrixRow{},
+		refreshInterval:  mb.refreshInterval,
+		writer:           mb.writer,
+		mx:               &sync.RWMutex{},
+		terminalHeightFn: mb.terminalHeightFn,
+		terminalWidthFn:  mb.terminalWidthFn,
+		viewportPolicy:   mb.viewportPolicy,
+		now:              time.Now,
 	}
 }
 
@@ -143,32 +280,189 @@ func (m *matrixImpl) GetRow(index int) (row MatrixRow, err error) {
 }
 
 func (m *matrixImpl) GetRowByID(id MatrixCellID) (row MatrixRow, err error) {
-	return m.GetRow(id.row)
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	index, err := m.indexOf(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.rows[index], nil
 }
 
-func (m *matrixImpl) UpdateTerminal(resetCursorPosition bool) {
-	c := NewCursor(m.writer)
+func (m *matrixImpl) IndexOf(id MatrixCellID) (int, error) {
 	m.mx.Lock()
 	defer m.mx.Unlock()
 
-	if len(m.rows) == 0 {
-		return
+	return m.indexOf(id)
+}
+
+func (m *matrixImpl) InsertRow(index int) (MatrixRow, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if index < 0 || index > len(m.rows) {
+		return nil, errors.New("row index exceeds the matrix range")
+	}
+
+	row := m.allocateRow()
+	m.rows = append(m.rows, nil)
+	copy(m.rows[index+1:], m.rows[index:])
+	m.rows[index] = row
+
+	return row, nil
+}
+
+func (m *matrixImpl) DeleteRow(id MatrixCellID) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	index, err := m.indexOf(id)
+	if err != nil {
+		return err
 	}
 
-	for _, row := range m.rows {
-		if row.modified {
-			_, err := io.WriteString(m.writer, fmt.Sprintf("%s%s\n", TermControlEraseLine, row.value))
-			row.modified = err != nil
-		} else {
-			_, _ = io.WriteString(m.writer, "\n")
+	m.rows[index].deleted = true
+	m.rows = append(m.rows[:index], m.rows[index+1:]...)
+
+	return nil
+}
+
+func (m *matrixImpl) MoveRow(id MatrixCellID, index int) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	from, err := m.indexOf(id)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(m.rows) {
+		return errors.New("row index exceeds the matrix range")
+	}
+
+	row := m.rows[from]
+	if from < index {
+		copy(m.rows[from:index], m.rows[from+1:index+1])
+	} else {
+		copy(m.rows[index+1:from+1], m.rows[index:from])
+	}
+	m.rows[index] = row
+
+	return nil
+}
+
+func (m *matrixImpl) SwapRows(a, b MatrixCellID) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	i, err := m.indexOf(a)
+	if err != nil {
+		return err
+	}
+	j, err := m.indexOf(b)
+	if err != nil {
+		return err
+	}
+
+	m.rows[i], m.rows[j] = m.rows[j], m.rows[i]
+
+	return nil
+}
+
+// indexOf returns the current index of the row with the specified ID. Must be called while holding mx.
+func (m *matrixImpl) indexOf(id MatrixCellID) (int, error) {
+	for i, row := range m.rows {
+		if row.id == id {
+			return i, nil
+		}
+	}
+
+	return -1, errors.New("no such row in the matrix")
+}
+
+func (m *matrixImpl) UpdateTerminal(resetCursorPosition bool) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	lines := m.frameLines(resetCursorPosition)
+	frame := new(strings.Builder)
+	screen := append([]string(nil), m.screen...)
+	line := 0
+
+	// rewrite the lines that differ from what's on screen, including the lines vacated by deleted rows
+	for i := 0; i < max(len(lines), len(screen)); i++ {
+		value := ""
+		if i < len(lines) {
+			value = lines[i]
+		}
+		if (i < len(screen) && screen[i] == value) || (i >= len(screen) && value == "") {
+			continue
+		}
+
+		writeCursorDown(frame, line, i, len(screen)-1)
+		for len(screen) <= i {
+			screen = append(screen, "")
 		}
+		frame.WriteString(TermControlEraseLine + value)
+		screen[i] = value
+		line = i
+	}
+
+	target := 0
+	if !resetCursorPosition {
+		target = len(lines)
+	}
+	if line < target {
+		writeCursorDown(frame, line, target, len(screen)-1)
+	} else if line > target {
+		NewCursor(frame).Up(line - target)
+	}
+
+	if frame.Len() == 0 {
+		return
+	}
+	if _, err := io.WriteString(m.writer, frame.String()); err != nil {
+		return
 	}
 
 	if resetCursorPosition {
-		c.Up(len(m.rows))
+		m.screen = screen
+	} else {
+		// the cursor is below the matrix now, so the next update draws it from scratch
+		m.screen = nil
 	}
 }
 
+// frameLines returns the lines of the next frame, fitted to the width of the terminal. Must be called while holding mx.
+func (m *matrixImpl) frameLines(animate bool) []string {
+	columns := m.terminalWidthFn()
+	rows, omitted := m.visibleRows()
+	lines := make([]string, 0, len(rows)+1)
+	// rows can span several lines, so the lines of the frame are not aligned with the rows
+	for _, row := range rows {
+		lines = append(lines, row.fit(columns, animate)...)
+	}
+	if omitted > 0 {
+		lines = append(lines, fitColumns(fmt.Sprintf(matrixMoreRowsFmt, omitted), columns, MatrixRowOverflowClip))
+	}
+
+	return lines
+}
+
+// writeCursorDown moves the cursor down from one line of the matrix to another. Line feeds are used beyond the last
+// line on screen, since the terminal doesn't move the cursor past it, and for short distances, where they are cheaper
+// than a cursor movement sequence.
+func writeCursorDown(frame *strings.Builder, from, to, lastLine int) {
+	lines := to - from
+	if jump := min(to, lastLine) - from; jump > maxLineFeedCursorMoves {
+		NewCursor(frame).Down(jump)
+		lines -= jump
+	}
+
+	frame.WriteString(strings.Repeat("\n", lines))
+}
+
 func (m *matrixImpl) NewRange(count int) []MatrixRow {
 	m.mx.Lock()
 	defer m.mx.Unlock()
@@ -189,12 +483,19 @@ func (m *matrixImpl) NewRow() MatrixRow {
 }
 
 func (m *matrixImpl) newRow() MatrixRow {
-	index := len(m.rows)
+	row := m.allocateRow()
+	m.rows = append(m.rows, row)
+
+	return row
+}
+
+// allocateRow creates a row with a new ID. Must be called while holding mx.
+func (m *matrixImpl) allocateRow() *matrixRow {
 	row := &matrixRow{
-		id:     MatrixCellID{row: index},
+		id:     MatrixCellID{row: m.nextID},
 		matrix: m,
 	}
-	m.rows = append(m.rows, row)
+	m.nextID++
 
 	return row
 }
@@ -207,10 +508,19 @@ func (r *matrixRow) Write(b []byte) (n int, err error) {
 	r.matrix.mx.Lock()
 	defer r.matrix.mx.Unlock()
 
-	row := r.matrix.rows[r.id.row]
-	newValue := strings.Trim(string(b), "\n\r")
-	row.modified = newValue != row.value
-	row.value = newValue
+	if r.deleted {
+		return len(b), nil
+	}
+
+	lines := strings.Split(strings.Trim(string(b), "\n\r"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	newValue := strings.Join(lines, "\n")
+	if newValue != r.value {
+		r.value = newValue
+		r.updated = r.matrix.now()
+	}
 
 	return len(b), nil
 }
@@ -222,3 +532,36 @@ func (r *matrixRow) Update(s string) {
 func (r *matrixRow) ID() MatrixCellID {
 	return r.id
 }
+
+func (r *matrixRow) SetPinned(pinned bool) {
+	r.matrix.mx.Lock()
+	defer r.matrix.mx.Unlock()
+
+	r.pinned = pinned
+}
+
+func (r *matrixRow) SetDone(done bool) {
+	r.matrix.mx.Lock()
+	defer r.matrix.mx.Unlock()
+
+	r.done = done
+}
+
+func (r *matrixRow) SetOverflow(overflow MatrixRowOverflow) {
+	r.matrix.mx.Lock()
+	defer r.matrix.mx.Unlock()
+
+	r.overflow = overflow
+}
+
+// lines returns the lines of this row. Must be called while holding the matrix mx.
+func (r *matrixRow) lines() []string {
+	return strings.Split(r.value, "\n")
+}
+
+func (r *matrixRow) SetHidden(hidden bool) {
+	r.matrix.mx.Lock()
+	defer r.matrix.mx.Unlock()
+
+	r.hidden = hidden
+}
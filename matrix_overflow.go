@@ -0,0 +1,67 @@
+package termite
+
+import "strings"
+
+const (
+	// matrixEllipsis marks the end of a row that was cut to fit the terminal width
+	matrixEllipsis = "…"
+
+	// matrixMarqueeGap separates the end of a scrolling row from its start
+	matrixMarqueeGap = "   "
+)
+
+// MatrixRowOverflow determines how a Matrix row that is wider than the terminal is fitted to it, so it never wraps.
+type MatrixRowOverflow int
+
+const (
+	// MatrixRowOverflowEllipsis cuts the row at the terminal width and marks the cut with an ellipsis
+	MatrixRowOverflowEllipsis MatrixRowOverflow = iota
+	// MatrixRowOverflowClip cuts the row at the terminal width
+	MatrixRowOverflowClip
+	// MatrixRowOverflowMarquee scrolls the row through the terminal width by one column on every update
+	MatrixRowOverflowMarquee
+)
+
+// fit returns the lines of this row fitted to the specified number of columns. Scrolling lines advance when animate
+// is set and are cut with an ellipsis otherwise. Must be called while holding the matrix mx.
+func (r *matrixRow) fit(columns int, animate bool) []string {
+	lines := r.lines()
+	scrolling := false
+	for i, line := range lines {
+		if columns <= 0 || displayWidth(line) <= columns {
+			continue
+		}
+
+		switch {
+		case r.overflow != MatrixRowOverflowMarquee:
+			lines[i] = fitColumns(line, columns, r.overflow)
+		case !animate:
+			lines[i] = fitColumns(line, columns, MatrixRowOverflowEllipsis)
+		default:
+			period := displayWidth(line) + len(matrixMarqueeGap)
+			lines[i] = sliceDisplayColumns(strings.Join([]string{line, line}, matrixMarqueeGap), r.marqueeOffset%period, columns)
+			scrolling = true
+		}
+	}
+
+	if scrolling {
+		r.marqueeOffset++
+	} else {
+		r.marqueeOffset = 0
+	}
+
+	return lines
+}
+
+// fitColumns cuts s to the specified number of columns, marking the cut with an ellipsis unless overflow is
+// MatrixRowOverflowClip.
+func fitColumns(s string, columns int, overflow MatrixRowOverflow) string {
+	if columns <= 0 || displayWidth(s) <= columns {
+		return s
+	}
+	if overflow == MatrixRowOverflowClip {
+		return sliceDisplayColumns(s, 0, columns)
+	}
+
+	return sliceDisplayColumns(s, 0, columns-displayWidth(matrixEllipsis)) + matrixEllipsis
+}
@@ -0,0 +1,111 @@
+package termite
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixRowOverflowEllipsisByDefault(t *testing.T) {
+	matrix := newOverflowMatrix(10)
+	matrix.NewRow().Update("hello wonderful world")
+
+	assert.Equal(t, []string{"hello won…"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixRowOverflowClip(t *testing.T) {
+	matrix := newOverflowMatrix(10)
+	row := matrix.NewRow().(ExtendedMatrixRow)
+	row.SetOverflow(MatrixRowOverflowClip)
+	row.Update("hello wonderful world")
+
+	assert.Equal(t, []string{"hello wond"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixRowOverflowMarquee(t *testing.T) {
+	matrix := newOverflowMatrix(5)
+	row := matrix.NewRow().(ExtendedMatrixRow)
+	row.SetOverflow(MatrixRowOverflowMarquee)
+	row.Update("abcdefgh")
+
+	var frames []string
+	for i := 0; i < 12; i++ {
+		frames = append(frames, matrix.(*matrixImpl).frameLines(true)[0])
+	}
+
+	assert.Equal(t, []string{"abcde", "bcdef", "cdefg", "defgh", "efgh ", "fgh  ", "gh   ", "h   a", "   ab", "  abc", " abcd", "abcde"}, frames)
+	assert.Equal(t, []string{"abcd…"}, matrix.(*matrixImpl).frameLines(false))
+}
+
+func TestMatrixRowOverflowMeasuresDisplayWidth(t *testing.T) {
+	matrix := newOverflowMatrix(6)
+	colored := matrix.NewRow()
+	colored.Update("\x1b[32mabcdef\x1b[0m")
+	wide := matrix.NewRow()
+	wide.Update("日本語です")
+
+	assert.Equal(t, []string{"\x1b[32mabcdef\x1b[0m", "日本…"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixRowOverflowFitsEachLine(t *testing.T) {
+	matrix := newOverflowMatrix(5)
+	row := matrix.NewRow().(ExtendedMatrixRow)
+	row.SetOverflow(MatrixRowOverflowMarquee)
+	row.Update("abc\nabcdefgh")
+
+	assert.Equal(t, []string{"abc", "abcde"}, matrix.(*matrixImpl).frameLines(true))
+	assert.Equal(t, []string{"abc", "bcdef"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixRowOverflowExpandsTabs(t *testing.T) {
+	matrix := newOverflowMatrix(10)
+	matrix.NewRow().Update("ab\tcdefghijklmnop")
+
+	assert.Equal(t, []string{"ab      c…"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixRowOverflowFollowsTerminalWidth(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	width := 0
+	matrix := NewMatrixBuilder().
+		WithWriter(emulatedOutput).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return width }).
+		Build()
+	matrix.NewRow().Update("hello world")
+
+	matrix.UpdateTerminal(true)
+	assert.Equal(t, TermControlEraseLine+"hello world", emulatedOutput.String())
+
+	emulatedOutput.Reset()
+	width = 6
+	matrix.UpdateTerminal(true)
+	assert.Equal(t, TermControlEraseLine+"hello…", emulatedOutput.String())
+}
+
+func TestMatrixViewportSummaryLineFitsTerminalWidth(t *testing.T) {
+	matrix := NewMatrixBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithRefreshInterval(time.Hour).
+		WithTerminalHeightFn(func() int { return 3 }).
+		WithTerminalWidthFn(func() int { return 4 }).
+		Build()
+	for i := 0; i < 1000; i++ {
+		matrix.NewRow().Update(fmt.Sprint(i))
+	}
+
+	lines := matrix.(*matrixImpl).frameLines(true)
+
+	assert.Equal(t, "+999", lines[len(lines)-1])
+}
+
+func newOverflowMatrix(width int) Matrix {
+	return NewMatrixBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return width }).
+		Build()
+}
@@ -3,6 +3,7 @@ package termite
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -50,12 +51,56 @@ func TestMatrixRowUpdateTrimsLineFeeds(t *testing.T) {
 }
 
 func TestNoRewritesWhenNothingChanges(t *testing.T) {
-	matrix, cancel := startNewMatrix()
-	defer cancel()
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrix(emulatedOutput, time.Hour)
+	matrix.NewRange(4)[1].Update(test.RandomString())
+	matrix.UpdateTerminal(true)
+	emulatedOutput.Reset()
+
+	matrix.UpdateTerminal(true)
+
+	assert.Empty(t, emulatedOutput.String())
+}
+
+func TestMatrixRewritesOnlyChangedLines(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrixBuilder().
+		WithWriter(emulatedOutput).
+		WithRefreshInterval(time.Hour).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		Build()
+	rows := matrix.NewRange(100)
+	for _, row := range rows {
+		row.Update(test.RandomString())
+	}
+	matrix.UpdateTerminal(true)
+	fullFrameLength := emulatedOutput.Len()
+	emulatedOutput.Reset()
+
+	rows[1].Update("b")
+	rows[50].Update("x")
+	matrix.UpdateTerminal(true)
+
+	expected := "\n" + TermControlEraseLine + "b" +
+		fmt.Sprintf(termControlCursorDownFmt, 49) + TermControlEraseLine + "x" +
+		fmt.Sprintf(termControlCursorUpFmt, 50)
+	assert.Equal(t, expected, emulatedOutput.String())
+	assert.Less(t, emulatedOutput.Len()*10, fullFrameLength)
+}
 
-	matrix.NewRange(4)
+func TestMatrixUpdateTerminalWithoutResetMovesBelowMatrix(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrix(emulatedOutput, time.Hour)
+	rows := matrix.NewRange(3)
+	rows[0].Update("a")
+	matrix.UpdateTerminal(true)
+	emulatedOutput.Reset()
+
+	rows[1].Update("b")
+	matrix.UpdateTerminal(false)
 
-	assertEventualSequence(t, matrix, expectedSkipRewriteSequenceFor(4))
+	assert.Equal(t, "\n"+TermControlEraseLine+"b\n\n", emulatedOutput.String())
 }
 
 func TestMatrixStructure(t *testing.T) {
@@ -142,6 +187,189 @@ func TestMatrixConcurrentStress(t *testing.T) {
 	}
 }
 
+func TestMatrixInsertRow(t *testing.T) {
+	matrix := NewMatrix(new(bytes.Buffer), time.Hour)
+	rows := matrix.NewRange(2)
+	rows[0].Update("a")
+	rows[1].Update("c")
+
+	row, err := matrix.InsertRow(1)
+	assert.NoError(t, err)
+	row.Update("b")
+	first, err := matrix.InsertRow(0)
+	assert.NoError(t, err)
+	first.Update("0")
+	last, err := matrix.InsertRow(4)
+	assert.NoError(t, err)
+	last.Update("d")
+
+	assert.Equal(t, []string{"0", "a", "b", "c", "d"}, linesOf(matrix))
+	assertIndexOf(t, matrix, rows[1].ID(), 3)
+
+	_, err = matrix.InsertRow(6)
+	assert.Error(t, err)
+	_, err = matrix.InsertRow(-1)
+	assert.Error(t, err)
+}
+
+func TestMatrixDeleteRow(t *testing.T) {
+	matrix := NewMatrix(new(bytes.Buffer), time.Hour)
+	rows := matrix.NewRange(3)
+	for i, value := range []string{"a", "b", "c"} {
+		rows[i].Update(value)
+	}
+
+	assert.NoError(t, matrix.DeleteRow(rows[1].ID()))
+	rows[1].Update("ignored")
+
+	assert.Equal(t, []string{"a", "c"}, linesOf(matrix))
+	assertIndexOf(t, matrix, rows[2].ID(), 1)
+	assert.Error(t, matrix.DeleteRow(rows[1].ID()))
+	_, err := matrix.GetRowByID(rows[1].ID())
+	assert.Error(t, err)
+
+	newRow := matrix.NewRow()
+	assert.NotEqual(t, rows[2].ID(), newRow.ID())
+	fetchedRow, err := matrix.GetRowByID(rows[2].ID())
+	assert.NoError(t, err)
+	assert.Equal(t, rows[2], fetchedRow)
+}
+
+func TestMatrixMoveRow(t *testing.T) {
+	matrix := NewMatrix(new(bytes.Buffer), time.Hour)
+	rows := matrix.NewRange(4)
+	for i, value := range []string{"a", "b", "c", "d"} {
+		rows[i].Update(value)
+	}
+
+	assert.NoError(t, matrix.MoveRow(rows[3].ID(), 0))
+	assert.Equal(t, []string{"d", "a", "b", "c"}, linesOf(matrix))
+
+	assert.NoError(t, matrix.MoveRow(rows[3].ID(), 2))
+	assert.Equal(t, []string{"a", "b", "d", "c"}, linesOf(matrix))
+
+	assert.Error(t, matrix.MoveRow(rows[0].ID(), 4))
+	assert.Equal(t, 3, rows[3].ID().Row())
+}
+
+func TestMatrixSwapRows(t *testing.T) {
+	matrix := NewMatrix(new(bytes.Buffer), time.Hour)
+	rows := matrix.NewRange(3)
+	for i, value := range []string{"a", "b", "c"} {
+		rows[i].Update(value)
+	}
+
+	assert.NoError(t, matrix.SwapRows(rows[0].ID(), rows[2].ID()))
+
+	assert.Equal(t, []string{"c", "b", "a"}, linesOf(matrix))
+	assertIndexOf(t, matrix, rows[0].ID(), 2)
+	assert.Error(t, matrix.SwapRows(rows[0].ID(), MatrixCellID{row: 10}))
+}
+
+func TestMatrixReorderingRewritesMovedRows(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrix(emulatedOutput, time.Hour)
+	rows := matrix.NewRange(3)
+	for i, value := range []string{"a", "b", "c"} {
+		rows[i].Update(value)
+	}
+	matrix.UpdateTerminal(true)
+	emulatedOutput.Reset()
+
+	assert.NoError(t, matrix.MoveRow(rows[2].ID(), 1))
+	matrix.UpdateTerminal(true)
+
+	assert.Equal(t, "\n"+expectedRewriteSequenceFor([]string{"c", "b"})+fmt.Sprintf(termControlCursorUpFmt, 2), emulatedOutput.String())
+}
+
+func TestMatrixDeleteRowClearsVacatedLines(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrix(emulatedOutput, time.Hour)
+	rows := matrix.NewRange(3)
+	for i, value := range []string{"a", "b", "c"} {
+		rows[i].Update(value)
+	}
+	matrix.UpdateTerminal(true)
+	emulatedOutput.Reset()
+
+	assert.NoError(t, matrix.DeleteRow(rows[0].ID()))
+	assert.NoError(t, matrix.DeleteRow(rows[1].ID()))
+	matrix.UpdateTerminal(true)
+
+	expected := expectedRewriteSequenceFor([]string{"c", "", ""})
+	assert.Equal(t, expected+fmt.Sprintf(termControlCursorUpFmt, 2), emulatedOutput.String())
+
+	emulatedOutput.Reset()
+	matrix.UpdateTerminal(true)
+	assert.Empty(t, emulatedOutput.String())
+}
+
+func TestMatrixMultiLineRows(t *testing.T) {
+	matrix := NewMatrixBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		Build()
+	matrix.NewRow().Update("\nheader\nline 1\r\nline 2\n")
+	matrix.NewRow().Update("footer")
+
+	assert.Equal(t, []string{"header", "line 1", "line 2", "footer"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixMultiLineRowsGrowAndShrink(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrixBuilder().
+		WithWriter(emulatedOutput).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		Build()
+	rows := matrix.NewRange(2)
+	rows[0].Update("a")
+	rows[1].Update("b")
+	matrix.UpdateTerminal(true)
+
+	emulatedOutput.Reset()
+	rows[0].Update("a\nx\ny")
+	matrix.UpdateTerminal(true)
+	assert.Equal(t, "\n"+expectedRewriteSequenceFor([]string{"x", "y", "b"})+fmt.Sprintf(termControlCursorUpFmt, 3), emulatedOutput.String())
+
+	emulatedOutput.Reset()
+	rows[0].Update("a")
+	matrix.UpdateTerminal(true)
+	assert.Equal(t, "\n"+expectedRewriteSequenceFor([]string{"b", "", ""})+fmt.Sprintf(termControlCursorUpFmt, 3), emulatedOutput.String())
+
+	emulatedOutput.Reset()
+	matrix.UpdateTerminal(false)
+	assert.Equal(t, "\n\n", emulatedOutput.String())
+}
+
+func TestMatrixHiddenRows(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrixBuilder().
+		WithWriter(emulatedOutput).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		Build()
+	rows := matrix.NewRange(3)
+	updateRows(rows)
+	matrix.UpdateTerminal(true)
+
+	emulatedOutput.Reset()
+	rows[1].(ExtendedMatrixRow).SetHidden(true)
+	matrix.UpdateTerminal(true)
+	assert.Equal(t, "\n"+expectedRewriteSequenceFor([]string{"row 2", ""})+fmt.Sprintf(termControlCursorUpFmt, 2), emulatedOutput.String())
+
+	rows[1].(ExtendedMatrixRow).SetHidden(false)
+	assert.Equal(t, []string{"row 0", "row 1", "row 2"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func assertIndexOf(t *testing.T, matrix Matrix, id MatrixCellID, expected int) {
+	index, err := matrix.IndexOf(id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, index)
+}
+
 func assertEventualSequence(t *testing.T, matrix Matrix, expected string) {
 	contantsAllExamplesInOrderFn := func() bool {
 		return strings.Contains(
@@ -157,17 +385,13 @@ func assertEventualSequence(t *testing.T, matrix Matrix, expected string) {
 	)
 }
 
-func expectedSkipRewriteSequenceFor(count int) string {
-	return strings.Repeat("\n", count)
-}
-
 func expectedRewriteSequenceFor(examples []string) string {
-	buf := new(bytes.Buffer)
-	for _, e := range examples {
-		buf.WriteString(TermControlEraseLine + e + "\n")
+	lines := make([]string, len(examples))
+	for i, e := range examples {
+		lines[i] = TermControlEraseLine + e
 	}
 
-	return buf.String()
+	return strings.Join(lines, "\n")
 }
 
 func startNewMatrix() (Matrix, context.CancelFunc) {
@@ -0,0 +1,102 @@
+package termite
+
+import (
+	"sort"
+	"time"
+)
+
+// matrixMoreRowsFmt the format of the summary line that replaces the rows that don't fit on the terminal
+const matrixMoreRowsFmt = "+%d more"
+
+// MatrixRowState the state of a Matrix row as seen by a MatrixViewportPolicy.
+type MatrixRowState struct {
+	// ID the ID of the row
+	ID MatrixCellID
+	// Index the current index of the row in the matrix
+	Index int
+	// Updated the last time the value of the row changed, or the zero time if it never did
+	Updated time.Time
+	// Pinned whether or not the row is pinned
+	Pinned bool
+	// Done whether or not the row is marked done
+	Done bool
+}
+
+// MatrixViewportPolicy decides which rows stay visible when a Matrix has more rows than fit on the terminal.
+// It reports whether row a should rather be visible than row b. Pinned rows are visible before any other row
+// regardless of the policy, and visible rows are always rendered in their matrix order.
+type MatrixViewportPolicy func(a, b MatrixRowState) bool
+
+// MatrixViewportTopRows keeps the rows at the top of the matrix visible.
+func MatrixViewportTopRows(a, b MatrixRowState) bool {
+	return a.Index < b.Index
+}
+
+// MatrixViewportRecentlyUpdated keeps the most recently updated rows visible.
+func MatrixViewportRecentlyUpdated(a, b MatrixRowState) bool {
+	if !a.Updated.Equal(b.Updated) {
+		return a.Updated.After(b.Updated)
+	}
+
+	return MatrixViewportTopRows(a, b)
+}
+
+// MatrixViewportRunningFirst keeps the rows that are not done visible, most recently updated first.
+func MatrixViewportRunningFirst(a, b MatrixRowState) bool {
+	if a.Done != b.Done {
+		return !a.Done
+	}
+
+	return MatrixViewportRecentlyUpdated(a, b)
+}
+
+// visibleRows returns the rows to render, which are all the rows that aren't hidden if their lines fit on the
+// terminal, or the rows selected by the viewport policy along with the number of rows left out to make room for a
+// summary line otherwise. Rows are selected in order of precedence as long as their lines fit, so a shorter row can
+// make it in place of a taller one. Must be called while holding mx.
+func (m *matrixImpl) visibleRows() (rows []*matrixRow, omitted int) {
+	states := make([]MatrixRowState, 0, len(m.rows))
+	lineCount := 0
+	for i, row := range m.rows {
+		if row.hidden {
+			continue
+		}
+		states = append(states, MatrixRowState{ID: row.id, Index: i, Updated: row.updated, Pinned: row.pinned, Done: row.done})
+		rows = append(rows, row)
+		lineCount += len(row.lines())
+	}
+
+	height := m.terminalHeightFn()
+	// one line is reserved for the cursor, which rests below the matrix once it stops
+	if height <= 0 || lineCount < height {
+		return rows, 0
+	}
+
+	capacity := max(height-2, 0)
+	sort.SliceStable(states, func(i, j int) bool {
+		if states[i].Pinned != states[j].Pinned {
+			return states[i].Pinned
+		}
+
+		return m.viewportPolicy(states[i], states[j])
+	})
+
+	visible := make([]bool, len(m.rows))
+	used := 0
+	for _, state := range states {
+		if rowHeight := len(m.rows[state.Index].lines()); used+rowHeight <= capacity {
+			visible[state.Index] = true
+			used += rowHeight
+		}
+	}
+
+	candidates := len(rows)
+	rows = rows[:0]
+	for i, row := range m.rows {
+		if visible[i] {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, candidates - len(rows)
+}
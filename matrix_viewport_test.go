@@ -0,0 +1,149 @@
+package termite
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixViewportShowsAllRowsWhenTheyFit(t *testing.T) {
+	matrix := newViewportMatrix(5, MatrixViewportTopRows)
+	updateRows(matrix.NewRange(4))
+
+	assert.Equal(t, []string{"row 0", "row 1", "row 2", "row 3"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportTopRows(t *testing.T) {
+	matrix := newViewportMatrix(5, MatrixViewportTopRows)
+	updateRows(matrix.NewRange(10))
+
+	assert.Equal(t, []string{"row 0", "row 1", "row 2", "+7 more"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportRecentlyUpdated(t *testing.T) {
+	matrix := newViewportMatrix(4, MatrixViewportRecentlyUpdated)
+	rows := matrix.NewRange(6)
+	updateRows(rows)
+	rows[1].Update("row 1 again")
+
+	assert.Equal(t, []string{"row 1 again", "row 5", "+4 more"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportRunningFirst(t *testing.T) {
+	matrix := newViewportMatrix(4, MatrixViewportRunningFirst)
+	rows := matrix.NewRange(6)
+	updateRows(rows)
+	rows[4].(ExtendedMatrixRow).SetDone(true)
+	rows[5].(ExtendedMatrixRow).SetDone(true)
+
+	assert.Equal(t, []string{"row 2", "row 3", "+4 more"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportKeepsPinnedRowsVisible(t *testing.T) {
+	matrix := newViewportMatrix(4, MatrixViewportRecentlyUpdated)
+	rows := matrix.NewRange(6)
+	rows[0].(ExtendedMatrixRow).SetPinned(true)
+	updateRows(rows)
+
+	assert.Equal(t, []string{"row 0", "row 5", "+4 more"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportCountsLinesOfMultiLineRows(t *testing.T) {
+	matrix := newViewportMatrix(5, MatrixViewportTopRows)
+	rows := matrix.NewRange(4)
+	updateRows(rows)
+	rows[1].Update("row 1\noutput")
+
+	assert.Equal(t, []string{"row 0", "row 1", "output", "+2 more"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportPrefersRowsThatFit(t *testing.T) {
+	matrix := newViewportMatrix(5, MatrixViewportTopRows)
+	rows := matrix.NewRange(4)
+	updateRows(rows)
+	rows[0].Update("row 0\nfirst\nsecond")
+
+	assert.Equal(t, []string{"row 0", "first", "second", "+3 more"}, matrix.(*matrixImpl).frameLines(true))
+
+	rows[0].Update("row 0\nfirst\nsecond\nthird")
+	assert.Equal(t, []string{"row 1", "row 2", "row 3", "+1 more"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportSkipsHiddenRows(t *testing.T) {
+	matrix := newViewportMatrix(4, MatrixViewportTopRows)
+	rows := matrix.NewRange(5)
+	updateRows(rows)
+	rows[0].(ExtendedMatrixRow).SetHidden(true)
+	assert.Equal(t, []string{"row 1", "row 2", "+2 more"}, matrix.(*matrixImpl).frameLines(true))
+
+	rows[1].(ExtendedMatrixRow).SetHidden(true)
+	assert.Equal(t, []string{"row 2", "row 3", "row 4"}, matrix.(*matrixImpl).frameLines(true))
+}
+
+func TestMatrixViewportRendersWithinTerminalHeight(t *testing.T) {
+	emulatedOutput := new(bytes.Buffer)
+	matrix := NewMatrixBuilder().
+		WithWriter(emulatedOutput).
+		WithRefreshInterval(time.Hour).
+		WithTerminalHeightFn(func() int { return 4 }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		WithViewportPolicy(MatrixViewportTopRows).
+		Build()
+	updateRows(matrix.NewRange(20))
+
+	matrix.UpdateTerminal(true)
+
+	expected := expectedRewriteSequenceFor([]string{"row 0", "row 1", "+18 more"}) + fmt.Sprintf(termControlCursorUpFmt, 2)
+	assert.Equal(t, expected, emulatedOutput.String())
+}
+
+func TestMatrixViewportFollowsTerminalHeight(t *testing.T) {
+	height := 4
+	matrix := NewMatrixBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTerminalHeightFn(func() int { return height }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		WithViewportPolicy(MatrixViewportTopRows).
+		Build()
+	updateRows(matrix.NewRange(4))
+
+	assert.Equal(t, []string{"row 0", "row 1", "+2 more"}, matrix.(*matrixImpl).frameLines(true))
+
+	height = 0
+	assert.Len(t, matrix.(*matrixImpl).frameLines(true), 4)
+}
+
+func TestMatrixBuilderDefaults(t *testing.T) {
+	matrix := NewMatrixBuilder().Build().(*matrixImpl)
+
+	assert.Equal(t, DefaultMatrixRefreshInterval, matrix.RefreshInterval())
+	assert.Equal(t, StdoutWriter, matrix.writer)
+	assert.NotNil(t, matrix.viewportPolicy)
+}
+
+func newViewportMatrix(height int, policy MatrixViewportPolicy) Matrix {
+	matrix := NewMatrixBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTerminalHeightFn(func() int { return height }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		WithViewportPolicy(policy).
+		Build()
+
+	// a clock that advances on every reading, so that later updates are always more recent
+	clock := time.Now()
+	matrix.(*matrixImpl).now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	return matrix
+}
+
+func updateRows(rows []MatrixRow) {
+	for i, row := range rows {
+		row.Update(fmt.Sprintf("row %d", i))
+	}
+}
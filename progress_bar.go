@@ -5,8 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
 )
 
 const (
@@ -22,7 +28,34 @@ const (
 	// DefaultProgressBarBlank default progress bar fill character
 	DefaultProgressBarBlank = '\u2591'
 
-	percentAreaSpace = 8
+	// DefaultProgressBarTemplate the default progress bar layout template
+	DefaultProgressBarTemplate = "{msg} {bar} {percent}{stats}"
+
+	// ProgressBarUnknownTotal a total value that puts a ProgressBar in indeterminate mode.
+	ProgressBarUnknownTotal int64 = -1
+
+	// DefaultProgressBarRefreshInterval the default minimal interval between two renderings of a progress bar
+	DefaultProgressBarRefreshInterval = time.Millisecond * 100
+
+	// progressBarPercentWidth the widest percent text, which the bar width is measured with
+	progressBarPercentWidth = len("100%")
+
+	// indeterminateFrameInterval the animation interval of an indeterminate progress bar
+	indeterminateFrameInterval = time.Millisecond * 100
+)
+
+// ProgressBarState the state of a ProgressBar
+type ProgressBarState int
+
+const (
+	// ProgressBarRunning a bar that accepts progress
+	ProgressBarRunning ProgressBarState = iota
+	// ProgressBarFinished a bar that has been ended by Finish
+	ProgressBarFinished
+	// ProgressBarFailed a bar that has been ended by Fail
+	ProgressBarFailed
+	// ProgressBarAborted a bar that has been ended by Abort
+	ProgressBarAborted
 )
 
 // DefaultProgressBarFormatter returns a new instance of the default ProgressBarFormatter
@@ -35,6 +68,12 @@ func DefaultProgressBarFormatter() *SimpleProgressBarFormatter {
 	}
 }
 
+// DefaultProgressBarPartialFillChars returns the eighth-block characters, ordered from the least to the most filled one.
+// These can be used as SimpleProgressBarFormatter.PartialFillChars for 8x fill resolution.
+func DefaultProgressBarPartialFillChars() []rune {
+	return []rune{'\u258F', '\u258E', '\u258D', '\u258C', '\u258B', '\u258A', '\u2589'}
+}
+
 // DefaultProgressBarFormatterWidth returns a default formatter with custom message area width.
 func DefaultProgressBarFormatterWidth(width int) *SimpleProgressBarFormatter {
 	return &SimpleProgressBarFormatter{
@@ -68,6 +107,61 @@ type ProgressBarFormatter interface {
 	MessageAreaWidth() int
 }
 
+// ProgressBarStatsFormatter an optional extension of ProgressBarFormatter that controls the elapsed time,
+// estimated time remaining and throughput segments displayed after the percentage.
+// Returning an empty string hides the respective segment.
+type ProgressBarStatsFormatter interface {
+	// FormatElapsed returns the elapsed time segment.
+	FormatElapsed(elapsed time.Duration) string
+
+	// FormatETA returns the estimated time remaining segment. A negative eta means it cannot be estimated yet.
+	FormatETA(eta time.Duration) string
+
+	// FormatRate returns the throughput segment. The rate is specified in units per second.
+	FormatRate(rate float64) string
+}
+
+// ProgressBarPartialFillFormatter an optional extension of ProgressBarFormatter that enables sub-character precision
+// rendering, by drawing the cell at the edge of the fill with a partially filled character.
+type ProgressBarPartialFillFormatter interface {
+	// FormatPartialFills returns the partially filled characters ordered from the least to the most filled one, each
+	// formatted like FormatFill. A sequence of n characters gives (n+1)x resolution. An empty sequence disables the
+	// partial fill rendering.
+	FormatPartialFills() []string
+}
+
+// ProgressBarTemplateFormatter an optional extension of ProgressBarFormatter that controls the layout of a progress bar line.
+//
+// A template is a string with any of the following tokens:
+//
+//	{msg}     - the tick message, right aligned to the message area width
+//	{bar}     - the bar including its borders, which takes the width that remains in the line
+//	{percent} - the completed percentage or, in indeterminate mode, the progress counter
+//	{current} - the current progress value
+//	{total}   - the total progress value or '?' in indeterminate mode
+//	{elapsed} - the elapsed time
+//	{eta}     - the estimated time remaining
+//	{rate}    - the throughput
+//	{stats}   - the elapsed time, ETA and throughput segments, each preceded by a space
+//
+// The {elapsed}, {eta}, {rate} and {stats} tokens are formatted by the ProgressBarStatsFormatter extension, if
+// implemented by the same formatter.
+type ProgressBarTemplateFormatter interface {
+	// LayoutTemplate returns the template to render progress bar lines with.
+	LayoutTemplate() string
+}
+
+// ProgressBarCompletionFormatter an optional extension of ProgressBarFormatter that styles the final line rendered
+// when a bar is ended by Finish or Fail.
+type ProgressBarCompletionFormatter interface {
+	// FormatFinalFill returns a string that contains one visible character and optionally additional styling
+	// characters, to fill the bar with when it ends in the specified state.
+	FormatFinalFill(state ProgressBarState) string
+
+	// FormatFinalMessage returns the message displayed at the end of the line when the bar ends in the specified state.
+	FormatFinalMessage(state ProgressBarState, message string) string
+}
+
 // SimpleProgressBarFormatter a simple ProgressBarFormatter implementation which is based on constructor values.
 type SimpleProgressBarFormatter struct {
 	LeftBorderChar  rune
@@ -75,6 +169,24 @@ type SimpleProgressBarFormatter struct {
 	FillChar        rune
 	BlankChar       rune
 	MessageWidth    int
+
+	// ShowElapsed whether or not to display the elapsed time
+	ShowElapsed bool
+	// ShowETA whether or not to display the estimated time remaining
+	ShowETA bool
+	// ShowRate whether or not to display the throughput
+	ShowRate bool
+	// RateUnit the unit displayed with the throughput, defaults to 'it' (items). Ignored when ByteRate is set.
+	RateUnit string
+	// ByteRate whether or not the throughput should be displayed in bytes per second, such as 1.5 MiB/s
+	ByteRate bool
+	// PartialFillChars partially filled characters for sub-character precision, such as DefaultProgressBarPartialFillChars
+	PartialFillChars []rune
+	// SegmentFills the fills of the segments of a stacked progress bar, each with one visible character and optional styling
+	SegmentFills []string
+	// Template the line layout template, defaults to DefaultProgressBarTemplate. See ProgressBarTemplateFormatter.
+	// Note that the stats tokens are subject to ShowElapsed, ShowETA and ShowRate.
+	Template string
 }
 
 // FormatLeftBorder returns the left border char
@@ -102,6 +214,80 @@ func (f *SimpleProgressBarFormatter) MessageAreaWidth() int {
 	return f.MessageWidth
 }
 
+// LayoutTemplate returns the template or DefaultProgressBarTemplate if it isn't set
+func (f *SimpleProgressBarFormatter) LayoutTemplate() string {
+	if f.Template == "" {
+		return DefaultProgressBarTemplate
+	}
+	return f.Template
+}
+
+// FormatPartialFills returns the partial fill chars
+func (f *SimpleProgressBarFormatter) FormatPartialFills() []string {
+	partialFills := make([]string, len(f.PartialFillChars))
+	for i, ch := range f.PartialFillChars {
+		partialFills[i] = fmt.Sprintf("%c", ch)
+	}
+	return partialFills
+}
+
+// FormatFinalFill returns the fill char in green for a finished bar and in red for a failed one
+func (f *SimpleProgressBarFormatter) FormatFinalFill(state ProgressBarState) string {
+	switch state {
+	case ProgressBarFinished:
+		return color.GreenString("%c", f.FillChar)
+	case ProgressBarFailed:
+		return color.RedString("%c", f.FillChar)
+	default:
+		return f.FormatFill()
+	}
+}
+
+// FormatFinalMessage returns the message in red for a failed bar and as is otherwise
+func (f *SimpleProgressBarFormatter) FormatFinalMessage(state ProgressBarState, message string) string {
+	if state == ProgressBarFailed {
+		return color.New(color.FgRed).Sprint(message)
+	}
+	return message
+}
+
+// FormatElapsed returns the elapsed time if ShowElapsed is set
+func (f *SimpleProgressBarFormatter) FormatElapsed(elapsed time.Duration) string {
+	if !f.ShowElapsed {
+		return ""
+	}
+	return formatDuration(elapsed)
+}
+
+// FormatETA returns the estimated time remaining if ShowETA is set
+func (f *SimpleProgressBarFormatter) FormatETA(eta time.Duration) string {
+	if !f.ShowETA {
+		return ""
+	}
+	if eta < 0 {
+		return "ETA --"
+	}
+	return "ETA " + formatDuration(eta)
+}
+
+// FormatRate returns the throughput if ShowRate is set
+func (f *SimpleProgressBarFormatter) FormatRate(rate float64) string {
+	if !f.ShowRate {
+		return ""
+	}
+	if f.ByteRate {
+		return formatBytes(rate) + "/s"
+	}
+	unit := f.RateUnit
+	if unit == "" {
+		unit = "it"
+	}
+	return fmt.Sprintf("%.1f %s/s", rate, unit)
+}
+
+// defaultStatsFormatter formats explicit stats template tokens for formatters that don't implement ProgressBarStatsFormatter
+var defaultStatsFormatter = &SimpleProgressBarFormatter{ShowElapsed: true, ShowETA: true, ShowRate: true}
+
 // TickMessageFn a tick handle
 type TickMessageFn = func(string) bool
 
@@ -113,20 +299,107 @@ type ProgressBar interface {
 	Start(context.Context) (TickMessageFn, error)
 }
 
-type bar struct {
-	maxTicks           int
-	ticks              int
-	writer             io.Writer
-	calculateWidth     func() int
-	formatter          ProgressBarFormatter
-	renderStringFormat string
-	active             bool
-	mx                 *sync.RWMutex
+// ExtendedProgressBar an extension of ProgressBar for progress in arbitrary units, explicit endings and child bars.
+// All the progress bars of this package implement it, so a ProgressBar returned by NewProgressBar can be type-asserted.
+type ExtendedProgressBar interface {
+	ProgressBar
+
+	// Add increments the progress by n units and returns whether or not the bar is still in progress.
+	Add(n int64) bool
+
+	// SetCurrent sets the absolute progress value and returns whether or not the bar is still in progress.
+	SetCurrent(current int64) bool
+
+	// SetTotal changes the value that is considered 100% of the progress.
+	// A negative total, such as ProgressBarUnknownTotal, puts the bar in indeterminate mode, in which an animated
+	// block slides inside the bar frame and a counter is displayed instead of a percentage.
+	SetTotal(total int64)
+
+	// Current returns the current progress value.
+	Current() int64
+
+	// Total returns the value that is considered 100% of the progress.
+	Total() int64
+
+	// Finish brings the bar to 100% and renders its final line with the specified message.
+	// A finished bar does not accept further progress.
+	Finish(message string)
+
+	// Fail renders the final line of this bar with the specified error. A failed bar does not accept further progress.
+	Fail(err error)
+
+	// Abort clears the line of this bar. An aborted bar does not accept further progress.
+	Abort()
+
+	// State returns the state of this bar.
+	State() ProgressBarState
+
+	// Err returns the error this bar failed with or nil if it hasn't failed.
+	Err() error
+
+	// NewChild creates a bar that contributes to the progress of this bar in proportion to its weight.
+	// Once a bar has children, its progress is the weighted sum of its children's progress and it is done when all of
+	// them have either ended or completed. The child renders to the specified writer, indented under its parent, or
+	// isn't rendered at all if the writer is nil. Non-positive weights are treated as 1.
+	NewChild(writer io.Writer, total int64, weight float64) ExtendedProgressBar
 }
 
-type progressEvent struct {
-	ok  bool
-	msg string
+// ProgressBarBuilder follows the builder pattern for creating a ProgressBar.
+type ProgressBarBuilder interface {
+	WithWriter(writer io.Writer) ProgressBarBuilder
+	WithTotal(total int64) ProgressBarBuilder
+	WithTerminalWidthFn(terminalWidthFn func() int) ProgressBarBuilder
+	WithWidth(width int) ProgressBarBuilder
+	WithFormatter(formatter ProgressBarFormatter) ProgressBarBuilder
+	// WithRefreshInterval sets the minimal interval between two renderings. Progress updates that happen in between
+	// are coalesced. A non-positive interval renders on every update, before the update returns.
+	WithRefreshInterval(refreshInterval time.Duration) ProgressBarBuilder
+	Build() ExtendedProgressBar
+	// WithCollapseFinishedChildren sets whether or not the lines of completed children are cleared, or hidden when
+	// they are MatrixRow lines.
+	WithCollapseFinishedChildren(collapse bool) ProgressBarBuilder
+	// BuildStacked builds a StackedProgressBar with the specified segment names or DefaultStackedProgressBarSegments.
+	BuildStacked(segments ...string) StackedProgressBar
+}
+
+type bar struct {
+	total           atomic.Int64
+	current         atomic.Int64
+	state           atomic.Int32
+	message         atomic.Pointer[string]
+	dirty           atomic.Bool
+	started         atomic.Bool
+	background      atomic.Bool
+	trailing        atomic.Bool
+	lastRender      atomic.Int64
+	segments        []atomic.Int64
+	segmentNames    []string
+	wakeC           chan struct{}
+	writer          io.Writer
+	calculateWidth  func(fixedWidth int) int
+	formatter       ProgressBarFormatter
+	refreshInterval time.Duration
+	mx              *sync.RWMutex
+	now             func() time.Time
+
+	// hierarchy
+	parent                   *bar
+	weight                   float64
+	indent                   string
+	collapseFinishedChildren bool
+	children                 []*bar
+	childrenMx               *sync.RWMutex
+
+	// the following fields are guarded by renderMx
+	renderMx      *sync.Mutex
+	trailingTimer *time.Timer
+	startTime     time.Time
+	rate          *rateEstimator
+	err           error
+	finalMessage  string
+	finalRendered bool
+	hidden        bool
+	layout        *progressBarLayout
 }
 
 // NewProgressBar creates a new progress bar
@@ -135,20 +408,38 @@ type progressEvent struct {
 // terminalWidthFn	- a funcrtion that returns the current terminal width
 // width 						- bar width in characters
 // formatter 		  	- a formatter for this progress bar
+//
+// The bar renders on every update, before the update returns. Use NewProgressBarBuilder for a bar that coalesces
+// renders.
 func NewProgressBar(writer io.Writer, maxTicks int, terminalWidthFn func() int, width int, formatter ProgressBarFormatter) ProgressBar {
-	renderFormat := fmt.Sprintf("%%s%%%ds %%s%%s%%s%%s %%d%%%%", formatter.MessageAreaWidth())
-	calculateWidth := func() int {
-		return max(0, min(width, terminalWidthFn()-percentAreaSpace-formatter.MessageAreaWidth()))
+	return newBar(writer, int64(maxTicks), terminalWidthFn, width, formatter, 0)
+}
+
+func newBar(writer io.Writer, total int64, terminalWidthFn func() int, width int, formatter ProgressBarFormatter, refreshInterval time.Duration) *bar {
+	calculateWidth := func(fixedWidth int) int {
+		return max(0, min(width, terminalWidthFn()-fixedWidth))
 	}
-	return &bar{
-		maxTicks:           maxTicks,
-		ticks:              0,
-		writer:             writer,
-		calculateWidth:     calculateWidth,
-		formatter:          formatter,
-		renderStringFormat: renderFormat,
-		mx:                 &sync.RWMutex{},
+	now := time.Now()
+	rate := &rateEstimator{}
+	rate.reset(0, now)
+
+	b := &bar{
+		wakeC:           make(chan struct{}, 1),
+		writer:          writer,
+		calculateWidth:  calculateWidth,
+		formatter:       formatter,
+		refreshInterval: refreshInterval,
+		mx:              &sync.RWMutex{},
+		now:             time.Now,
+		childrenMx:      &sync.RWMutex{},
+		renderMx:        &sync.Mutex{},
+		startTime:       now,
+		rate:            rate,
 	}
+	b.total.Store(total)
+	b.message.Store(new(string))
+
+	return b
 }
 
 // NewDefaultProgressBar creates a progress bar with styling
@@ -158,9 +449,105 @@ func NewDefaultProgressBar(writer io.Writer, maxTicks int, terminalWidthFn func(
 	)
 }
 
-// IsDone returns whether or not this progress bar has reached 100%
+type progressBarBuilder struct {
+	writer                   io.Writer
+	total                    int64
+	terminalWidthFn          func() int
+	width                    int
+	formatter                ProgressBarFormatter
+	refreshInterval          time.Duration
+	collapseFinishedChildren bool
+}
+
+// NewProgressBarBuilder creates a new ProgressBarBuilder with default values.
+// By default the bar writes to Stdout, takes half of the terminal width and has a total of 100.
+func NewProgressBarBuilder() ProgressBarBuilder {
+	return &progressBarBuilder{
+		writer: StdoutWriter,
+		total:  defaultProgressBarTotal,
+		terminalWidthFn: func() int {
+			width, _, _ := GetTerminalDimensions()
+			return width
+		},
+		width:           -1,
+		formatter:       DefaultProgressBarFormatter(),
+		refreshInterval: DefaultProgressBarRefreshInterval,
+	}
+}
+
+func (pb *progressBarBuilder) WithWriter(writer io.Writer) ProgressBarBuilder {
+	pb.writer = writer
+	return pb
+}
+
+func (pb *progressBarBuilder) WithTotal(total int64) ProgressBarBuilder {
+	pb.total = total
+	return pb
+}
+
+func (pb *progressBarBuilder) WithTerminalWidthFn(terminalWidthFn func() int) ProgressBarBuilder {
+	pb.terminalWidthFn = terminalWidthFn
+	return pb
+}
+
+func (pb *progressBarBuilder) WithWidth(width int) ProgressBarBuilder {
+	pb.width = width
+	return pb
+}
+
+func (pb *progressBarBuilder) WithFormatter(formatter ProgressBarFormatter) ProgressBarBuilder {
+	pb.formatter = formatter
+	return pb
+}
+
+func (pb *progressBarBuilder) WithRefreshInterval(refreshInterval time.Duration) ProgressBarBuilder {
+	pb.refreshInterval = refreshInterval
+	return pb
+}
+
+func (pb *progressBarBuilder) WithCollapseFinishedChildren(collapse bool) ProgressBarBuilder {
+	pb.collapseFinishedChildren = collapse
+	return pb
+}
+
+func (pb *progressBarBuilder) Build() ExtendedProgressBar {
+	return pb.build()
+}
+
+func (pb *progressBarBuilder) BuildStacked(segments ...string) StackedProgressBar {
+	return newStackedBar(pb.build(), segments)
+}
+
+func (pb *progressBarBuilder) build() *bar {
+	width := pb.width
+	if width < 0 {
+		width = pb.terminalWidthFn() / 2
+	}
+
+	b := newBar(pb.writer, pb.total, pb.terminalWidthFn, width, pb.formatter, pb.refreshInterval)
+	b.collapseFinishedChildren = pb.collapseFinishedChildren
+
+	return b
+}
+
+// IsDone returns whether or not this progress bar has reached 100%. An indeterminate bar is never done.
 func (b *bar) IsDone() bool {
-	return b.ticks >= b.maxTicks
+	if children := b.childBars(); len(children) > 0 {
+		return allChildrenComplete(children)
+	}
+
+	total := b.total.Load()
+	return total >= 0 && b.current.Load() >= total
+}
+
+// Current returns the current progress value
+func (b *bar) Current() int64 {
+	return b.progress()
+}
+
+// Total returns the value that is considered 100% of the progress
+func (b *bar) Total() int64 {
+	return b.total.Load()
 }
 
 // Tick increments the progress by one tick. Does not imply visual change.
@@ -170,22 +557,200 @@ func (b *bar) Tick() bool {
 
 // TickTickMessage increments the progress by one tick. Does not imply visual change.
 func (b *bar) TickMessage(message string) bool {
-	if b.IsDone() {
+	return b.add(1, message)
+}
+
+// Add increments the progress by n units. Does not imply visual change.
+func (b *bar) Add(n int64) bool {
+	return b.add(n, *b.message.Load())
+}
+
+// SetCurrent sets the absolute progress value. Does not imply visual change.
+func (b *bar) SetCurrent(current int64) bool {
+	if b.State() != ProgressBarRunning {
+		return false
+	}
+
+	if len(b.segments) > 0 {
+		// the first segment takes the difference, so the progress cannot go below the sum of the other segments
+		current = max(current, b.current.Load()-b.segments[0].Load())
+	}
+	previous := b.current.Swap(current)
+	if len(b.segments) > 0 {
+		b.segments[0].Add(current - previous)
+	}
+
+	return b.changed(false)
+}
+
+// SetTotal changes the value that is considered 100% of the progress.
+func (b *bar) SetTotal(total int64) {
+	if b.State() != ProgressBarRunning {
+		return
+	}
+
+	b.total.Store(total)
+	b.changed(true)
+}
+
+// Finish brings the bar to 100% and renders its final line with the specified message.
+func (b *bar) Finish(message string) {
+	b.end(ProgressBarFinished, message, nil)
+}
+
+// Fail renders the final line of this bar with the specified error.
+func (b *bar) Fail(err error) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	b.end(ProgressBarFailed, message, err)
+}
+
+// Abort clears the line of this bar.
+func (b *bar) Abort() {
+	b.end(ProgressBarAborted, "", nil)
+}
+
+// State returns the state of this bar
+func (b *bar) State() ProgressBarState {
+	return ProgressBarState(b.state.Load())
+}
+
+// Err returns the error this bar failed with
+func (b *bar) Err() error {
+	b.renderMx.Lock()
+	defer b.renderMx.Unlock()
+
+	return b.err
+}
+
+// end moves this bar to the specified final state and renders its final line, unless it has already ended.
+func (b *bar) end(state ProgressBarState, message string, err error) {
+	b.renderMx.Lock()
+	defer b.renderMx.Unlock()
+
+	if !b.state.CompareAndSwap(int32(ProgressBarRunning), int32(state)) {
+		return
+	}
+
+	if state == ProgressBarFinished {
+		current, total := b.current.Load(), b.total.Load()
+		// a stacked bar shrinks its total rather than attributing the remainder to any of its segments
+		if total < 0 || current > total || len(b.segments) > 0 {
+			b.total.Store(current)
+		} else {
+			b.current.Store(total)
+		}
+	}
+	b.err = err
+	b.finalMessage = message
+	b.render()
+	b.finalRendered = true
+	b.stopTrailingRender()
+	b.wake()
+
+	if b.parent != nil {
+		b.parent.childChanged()
+	}
+}
+
+func (b *bar) add(n int64, message string) bool {
+	return b.addTo(0, n, message)
+}
+
+func (b *bar) addTo(segment int, n int64, message string) bool {
+	if b.State() != ProgressBarRunning || b.IsDone() {
 		return false
 	}
 
-	b.ticks++
+	if message != *b.message.Load() {
+		b.message.Store(&message)
+	}
+	if len(b.segments) > 0 {
+		b.segments[segment].Add(n)
+	}
+	b.current.Add(n)
+
+	return b.changed(false)
+}
+
+// changed marks this bar for rendering and returns whether or not it is still in progress.
+//
+// A bar that is rendered in the background never blocks. Otherwise, it is rendered by the calling goroutine if the
+// refresh interval has elapsed since the last rendering, the bar is complete or force is set. An update that is not
+// rendered is followed by a trailing render once the refresh interval elapses.
+func (b *bar) changed(force bool) bool {
+	inProgress := !b.IsDone()
+	b.dirty.Store(true)
+
+	switch {
+	case b.background.Load() && b.refreshInterval > 0:
+		if force || !inProgress {
+			b.wake()
+		}
+
+	case force || !inProgress || b.refreshInterval <= 0:
+		b.renderMx.Lock()
+		b.render()
+		b.stopTrailingRender()
+		b.renderMx.Unlock()
+
+	default:
+		if since := b.now().UnixNano() - b.lastRender.Load(); since >= int64(b.refreshInterval) {
+			b.renderIfDirty()
+		} else if b.trailing.CompareAndSwap(false, true) {
+			b.scheduleTrailingRender(b.refreshInterval - time.Duration(since))
+		}
+	}
+
+	if b.parent != nil {
+		b.parent.childChanged()
+	}
 
-	return b.render(message)
+	return inProgress
+}
+
+// scheduleTrailingRender renders this bar after the specified delay, unless it is rendered in between.
+func (b *bar) scheduleTrailingRender(delay time.Duration) {
+	b.renderMx.Lock()
+	defer b.renderMx.Unlock()
+
+	b.trailingTimer = time.AfterFunc(delay, func() {
+		b.trailing.Store(false)
+		b.renderIfDirty()
+	})
+}
+
+// stopTrailingRender cancels a scheduled trailing render. Must be called while holding renderMx.
+func (b *bar) stopTrailingRender() {
+	if b.trailingTimer != nil && b.trailingTimer.Stop() {
+		b.trailing.Store(false)
+	}
+	b.trailingTimer = nil
+}
+
+// wake signals the background rendering routine without blocking.
+func (b *bar) wake() {
+	select {
+	case b.wakeC <- struct{}{}:
+	default:
+	}
 }
 
 // Start starts the progress bar in the background and returns a tick handle, a cancellation handle and an error in case
 // this bar has already been started.
+//
+// Once started with a positive refresh interval, progress updates never render synchronously. Instead, the bar is
+// rendered by a background routine at most once every refresh interval, and as soon as it completes. With a
+// non-positive refresh interval, which is the default of NewProgressBar and NewDefaultProgressBar, every update renders
+// before it returns, so callers can move the cursor in between. The routine exits when the context ends or the bar is
+// complete, once its final state has been rendered.
 func (b *bar) Start(ctx context.Context) (tick TickMessageFn, err error) {
 	defer b.mx.Unlock()
 	b.mx.Lock()
 
-	if b.active {
+	if b.started.Load() {
 		return nil, errors.New("progress bar already running in the background")
 	}
 
@@ -193,11 +758,14 @@ func (b *bar) Start(ctx context.Context) (tick TickMessageFn, err error) {
 		return nil, ctx.Err()
 	}
 
-	b.active = true
-	b.render("")
+	b.renderMx.Lock()
+	b.startTime = b.now()
+	b.rate.reset(b.progress(), b.startTime)
+	b.render()
+	b.renderMx.Unlock()
+	b.started.Store(true)
+	b.background.Store(true)
 
-	events := make(chan progressEvent)
-	var done bool
 	waitStart := &sync.WaitGroup{}
 	waitStart.Add(1)
 
@@ -206,24 +774,41 @@ func (b *bar) Start(ctx context.Context) (tick TickMessageFn, err error) {
 			return false
 		}
 
-		if !done {
-			events <- progressEvent{ok: true, msg: msg}
-			maybeDoneEvent := <-events
-			done = !maybeDoneEvent.ok
-		}
-		return !done
+		return b.TickMessage(msg)
 	}
 
 	go func() {
+		refreshInterval := b.refreshInterval
+		if refreshInterval <= 0 {
+			refreshInterval = indeterminateFrameInterval
+		}
+		refreshTicker := time.NewTicker(refreshInterval)
+		defer refreshTicker.Stop()
+		defer func() {
+			// updates that have not seen the routine exit may have left the bar dirty
+			b.background.Store(false)
+			b.renderIfDirty()
+		}()
+
 		waitStart.Done()
 		for {
 			select {
 			case <-ctx.Done():
 				return
 
-			case evt := <-events:
-				evt.ok = b.TickMessage(evt.msg)
-				events <- evt
+			case <-refreshTicker.C:
+				if b.isIndeterminate() {
+					// indeterminate bars animate regardless of progress updates
+					b.dirty.Store(true)
+				}
+				b.renderIfDirty()
+
+			case <-b.wakeC:
+				b.renderIfDirty()
+			}
+
+			if b.State() != ProgressBarRunning || b.IsDone() {
+				return
 			}
 		}
 	}()
@@ -233,25 +818,323 @@ func (b *bar) Start(ctx context.Context) (tick TickMessageFn, err error) {
 	return tick, err
 }
 
-func (b *bar) render(message string) bool {
-	totalChars := b.calculateWidth()
-	percent := float32(b.ticks) / float32(b.maxTicks)
-	charsToFill := int(percent * float32(totalChars))
-	spaceChars := totalChars - charsToFill
-
-	_, _ = io.WriteString(
-		b.writer,
-		fmt.Sprintf(
-			b.renderStringFormat,
-			TermControlEraseLine,
-			TruncateString(message, b.formatter.MessageAreaWidth()),
-			b.formatter.FormatLeftBorder(),
-			strings.Repeat(b.formatter.FormatFill(), charsToFill),
-			strings.Repeat(b.formatter.FormatBlank(), spaceChars),
-			b.formatter.FormatRightBorder(),
-			int(percent*100),
-		),
+// renderIfDirty renders this bar if it has changed since it was last rendered.
+func (b *bar) renderIfDirty() {
+	b.renderMx.Lock()
+	defer b.renderMx.Unlock()
+
+	if b.dirty.Load() {
+		b.render()
+	}
+}
+
+// render writes the current state of this bar to its writer. Must be called while holding renderMx.
+func (b *bar) render() {
+	if b.finalRendered {
+		return
+	}
+
+	now := b.now()
+	b.dirty.Store(false)
+	b.lastRender.Store(now.UnixNano())
+	if b.isCollapsed() && b.setHidden(true) {
+		return
+	}
+	if b.State() == ProgressBarAborted || b.isCollapsed() {
+		_, _ = io.WriteString(b.writer, TermControlEraseLine)
+		return
+	}
+	b.setHidden(false)
+
+	current := b.progress()
+	b.rate.update(current, now)
+	message := *b.message.Load()
+
+	template := DefaultProgressBarTemplate
+	if formatter, ok := b.formatter.(ProgressBarTemplateFormatter); ok {
+		template = formatter.LayoutTemplate()
+	}
+
+	if b.layout == nil || b.layout.template != template {
+		b.layout = newProgressBarLayout(template, b.formatter)
+	}
+
+	percent := b.renderProgressText()
+	tokens := make([]string, 0, 20)
+	tokens = append(tokens,
+		"{msg}", b.renderMessage(message),
+		"{current}", strconv.FormatInt(current, 10),
+		"{total}", b.renderTotal(),
+		"{percent}", percent,
 	)
+	if b.layout.hasStats {
+		tokens = append(tokens, b.renderStatsTokens(now)...)
+	}
+	tokens = append(tokens, b.renderSegmentTokens()...)
+
+	finalMessage := b.renderFinalMessage()
+	fixedWidth := b.layout.fixedWidth(tokens) + displayWidth(finalMessage)
+	if b.layout.hasBar {
+		// the percent is measured at its widest to keep the bar width stable as the progress changes
+		fixedWidth += b.layout.percentCount * max(0, progressBarPercentWidth-displayWidth(percent))
+		tokens = append(tokens, "{bar}", b.renderBar(b.calculateWidth(fixedWidth)))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(TermControlEraseLine)
+	sb.WriteString(b.indent)
+	b.layout.expand(&sb, tokens)
+	sb.WriteString(finalMessage)
+	_, _ = io.WriteString(b.writer, sb.String())
+}
+
+// renderMessage returns the message truncated and right aligned to the message area width.
+func (b *bar) renderMessage(message string) string {
+	areaWidth := b.formatter.MessageAreaWidth()
+	message = TruncateString(message, areaWidth)
+	if padding := areaWidth - utf8.RuneCountInString(message); padding > 0 {
+		return strings.Repeat(" ", padding) + message
+	}
+
+	return message
+}
+
+// progressBarLayout a layout template split into literal text and tokens, along with the display width of its
+// literal text and the bar borders, which are cached by a bar between renders.
+type progressBarLayout struct {
+	template     string
+	parts        []string
+	isToken      []bool
+	literalWidth int
+	hasBar       bool
+	hasStats     bool
+	percentCount int
+}
+
+func newProgressBarLayout(template string, formatter ProgressBarFormatter) *progressBarLayout {
+	layout := &progressBarLayout{template: template}
+	for rest := template; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest[max(start, 0):], '}') + max(start, 0)
+		if start < 0 || end < start {
+			layout.addLiteral(rest)
+			break
+		}
+
+		layout.addLiteral(rest[:start])
+		layout.parts = append(layout.parts, rest[start:end+1])
+		layout.isToken = append(layout.isToken, true)
+		switch rest[start : end+1] {
+		case "{bar}":
+			layout.hasBar = true
+		case "{percent}":
+			layout.percentCount++
+		case "{elapsed}", "{eta}", "{rate}", "{stats}":
+			layout.hasStats = true
+		}
+		rest = rest[end+1:]
+	}
+
+	if layout.hasBar {
+		layout.literalWidth += displayWidth(formatter.FormatLeftBorder() + formatter.FormatRightBorder())
+	}
+
+	return layout
+}
+
+func (l *progressBarLayout) addLiteral(text string) {
+	if text == "" {
+		return
+	}
+
+	l.parts = append(l.parts, text)
+	l.isToken = append(l.isToken, false)
+	l.literalWidth += displayWidth(text)
+}
+
+// fixedWidth returns the display width of a line rendered with the specified token values, excluding the bar frame.
+func (l *progressBarLayout) fixedWidth(tokens []string) int {
+	width := l.literalWidth
+	for i, part := range l.parts {
+		if l.isToken[i] && part != "{bar}" {
+			width += displayWidth(tokenValue(tokens, part))
+		}
+	}
+
+	return width
+}
+
+// expand writes the template with its tokens replaced by the specified values. Unknown tokens are kept as is.
+func (l *progressBarLayout) expand(sb *strings.Builder, tokens []string) {
+	for i, part := range l.parts {
+		if l.isToken[i] {
+			part = tokenValue(tokens, part)
+		}
+		sb.WriteString(part)
+	}
+}
+
+// tokenValue looks up a token in replacement pairs and returns its value, or the token itself if it has none.
+func tokenValue(tokens []string, token string) string {
+	for i := 0; i+1 < len(tokens); i += 2 {
+		if tokens[i] == token {
+			return tokens[i+1]
+		}
+	}
+
+	return token
+}
+
+// renderFinalMessage returns the message of an ended bar preceded by a space, or an empty string.
+func (b *bar) renderFinalMessage() string {
+	if b.State() == ProgressBarRunning || b.finalMessage == "" {
+		return ""
+	}
+
+	message := b.finalMessage
+	if formatter, ok := b.formatter.(ProgressBarCompletionFormatter); ok {
+		message = formatter.FormatFinalMessage(b.State(), message)
+	}
+
+	return " " + message
+}
+
+// renderBar returns the bar including its borders, where the bar frame is the specified number of characters wide.
+func (b *bar) renderBar(totalChars int) string {
+	return b.formatter.FormatLeftBorder() + b.renderFill(totalChars) + b.formatter.FormatRightBorder()
+}
+
+// renderFill returns the content of the bar frame, which is the specified number of characters wide.
+func (b *bar) renderFill(totalChars int) string {
+	if b.isIndeterminate() {
+		return b.renderIndeterminateFill(totalChars)
+	}
+	if len(b.segments) > 0 {
+		return b.renderStackedFill(totalChars)
+	}
+
+	fill := b.formatter.FormatFill()
+	var partialFills []string
+	if state := b.State(); state != ProgressBarRunning {
+		if formatter, ok := b.formatter.(ProgressBarCompletionFormatter); ok {
+			fill = formatter.FormatFinalFill(state)
+		}
+	} else if formatter, ok := b.formatter.(ProgressBarPartialFillFormatter); ok {
+		partialFills = formatter.FormatPartialFills()
+	}
+
+	resolution := len(partialFills) + 1
+	unitsToFill := int(b.fraction() * float64(totalChars*resolution))
+	charsToFill := unitsToFill / resolution
+	partialFill := ""
+	if remainder := unitsToFill % resolution; remainder > 0 {
+		partialFill = partialFills[remainder-1]
+	}
+
+	return strings.Repeat(fill, charsToFill) +
+		partialFill +
+		strings.Repeat(b.formatter.FormatBlank(), totalChars-charsToFill-displayWidth(partialFill))
+}
+
+// renderIndeterminateFill returns a block that bounces back and forth inside the bar frame based on the time elapsed.
+func (b *bar) renderIndeterminateFill(totalChars int) string {
+	blockChars := min(totalChars, max(1, totalChars/5))
+	travel := totalChars - blockChars
+	position := 0
+	if travel > 0 {
+		frame := int(b.now().Sub(b.startTime) / indeterminateFrameInterval)
+		position = frame % (2 * travel)
+		if position > travel {
+			position = 2*travel - position
+		}
+	}
+
+	return strings.Repeat(b.formatter.FormatBlank(), position) +
+		strings.Repeat(b.formatter.FormatFill(), blockChars) +
+		strings.Repeat(b.formatter.FormatBlank(), travel-position)
+}
+
+// renderProgressText returns the percentage or, in indeterminate mode, the progress counter.
+func (b *bar) renderProgressText() string {
+	if b.isIndeterminate() {
+		return strconv.FormatInt(b.progress(), 10)
+	}
+
+	return strconv.Itoa(int(b.fraction()*100)) + "%"
+}
+
+// renderTotal returns the total or '?' in indeterminate mode.
+func (b *bar) renderTotal() string {
+	if b.isIndeterminate() {
+		return "?"
+	}
+
+	return strconv.FormatInt(b.total.Load(), 10)
+}
+
+// renderStatsTokens returns template replacement pairs for the elapsed time, ETA and throughput tokens.
+func (b *bar) renderStatsTokens(now time.Time) []string {
+	formatter, ok := b.formatter.(ProgressBarStatsFormatter)
+	if !ok {
+		formatter = defaultStatsFormatter
+	}
+
+	elapsed := formatter.FormatElapsed(now.Sub(b.startTime))
+	eta := formatter.FormatETA(b.eta())
+	rate := formatter.FormatRate(b.rate.rate)
+
+	var stats strings.Builder
+	if ok {
+		for _, segment := range []string{elapsed, eta, rate} {
+			if segment != "" {
+				stats.WriteString(" ")
+				stats.WriteString(segment)
+			}
+		}
+	}
+
+	return []string{
+		"{elapsed}", elapsed,
+		"{eta}", eta,
+		"{rate}", rate,
+		"{stats}", stats.String(),
+	}
+}
+
+// eta returns the estimated time remaining or a negative duration if it cannot be estimated.
+func (b *bar) eta() time.Duration {
+	if b.isIndeterminate() {
+		return -1
+	}
+
+	return b.rate.eta(b.total.Load() - b.progress())
+}
+
+// isIndeterminate returns whether or not the total of this bar is unknown.
+func (b *bar) isIndeterminate() bool {
+	return b.total.Load() < 0 && !b.hasChildren()
+}
+
+// progress returns the current progress value, which for a parent bar is derived from the progress of its children.
+func (b *bar) progress() int64 {
+	if b.hasChildren() {
+		return int64(b.fraction() * float64(b.total.Load()))
+	}
+
+	return b.current.Load()
+}
+
+// fraction returns the completed fraction of this bar in the range of [0, 1].
+func (b *bar) fraction() float64 {
+	if b.State() == ProgressBarFinished {
+		return 1
+	}
+	if children := b.childBars(); len(children) > 0 {
+		return weightedFraction(children)
+	}
+	if b.total.Load() <= 0 {
+		return 1
+	}
 
-	return b.maxTicks > b.ticks
+	return max(0, min(1, float64(b.current.Load())/float64(b.total.Load())))
 }
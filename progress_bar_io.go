@@ -0,0 +1,111 @@
+package termite
+
+import (
+	"errors"
+	"io"
+)
+
+// NewProgressReader returns an io.Reader that reads from reader and advances the specified bar by the number of
+// bytes read. The bar is finished when reader reaches EOF and failed on any other error.
+//
+// The returned reader implements io.ReaderAt and io.Seeker if, and only if, reader implements them, so it can be
+// passed to code that type-asserts these interfaces. Seeking relative to the start or the current offset moves the
+// progress to the new offset. Seeking relative to the end, such as to probe the size, leaves the progress unchanged.
+func NewProgressReader(reader io.Reader, bar ExtendedProgressBar) io.Reader {
+	pr := &progressReader{reader: reader, bar: bar}
+	readerAt, isReaderAt := reader.(io.ReaderAt)
+	seeker, isSeeker := reader.(io.Seeker)
+
+	switch {
+	case isReaderAt && isSeeker:
+		return &progressReadSeekerAt{
+			progressReadSeeker: &progressReadSeeker{progressReader: pr, seeker: seeker},
+			readerAt:           readerAt,
+		}
+	case isSeeker:
+		return &progressReadSeeker{progressReader: pr, seeker: seeker}
+	case isReaderAt:
+		return &progressReaderAt{progressReader: pr, readerAt: readerAt}
+	default:
+		return pr
+	}
+}
+
+// NewProgressWriter returns an io.Writer that writes to writer and advances the specified bar by the number of
+// bytes written. The bar is failed if a write fails.
+func NewProgressWriter(writer io.Writer, bar ExtendedProgressBar) io.Writer {
+	return &progressWriter{writer: writer, bar: bar}
+}
+
+type progressReader struct {
+	reader io.Reader
+	bar    ExtendedProgressBar
+}
+
+func (r *progressReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		r.bar.Add(int64(n))
+	}
+
+	switch {
+	case errors.Is(err, io.EOF):
+		r.bar.Finish("")
+	case err != nil:
+		r.bar.Fail(err)
+	}
+
+	return n, err
+}
+
+type progressReaderAt struct {
+	*progressReader
+	readerAt io.ReaderAt
+}
+
+// ReadAt delegates to the underlying io.ReaderAt. Since it doesn't move the read offset, it doesn't affect the progress.
+func (r *progressReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	return r.readerAt.ReadAt(p, off)
+}
+
+type progressReadSeeker struct {
+	*progressReader
+	seeker io.Seeker
+}
+
+// Seek delegates to the underlying io.Seeker and sets the progress to the new offset, unless it is relative to the end.
+func (r *progressReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.seeker.Seek(offset, whence)
+	if err == nil && whence != io.SeekEnd {
+		r.bar.SetCurrent(pos)
+	}
+
+	return pos, err
+}
+
+type progressReadSeekerAt struct {
+	*progressReadSeeker
+	readerAt io.ReaderAt
+}
+
+// ReadAt delegates to the underlying io.ReaderAt. Since it doesn't move the read offset, it doesn't affect the progress.
+func (r *progressReadSeekerAt) ReadAt(p []byte, off int64) (n int, err error) {
+	return r.readerAt.ReadAt(p, off)
+}
+
+type progressWriter struct {
+	writer io.Writer
+	bar    ExtendedProgressBar
+}
+
+func (w *progressWriter) Write(p []byte) (n int, err error) {
+	n, err = w.writer.Write(p)
+	if n > 0 {
+		w.bar.Add(int64(n))
+	}
+	if err != nil {
+		w.bar.Fail(err)
+	}
+
+	return n, err
+}
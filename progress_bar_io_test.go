@@ -0,0 +1,174 @@
+package termite
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sha1n/gommons/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressReaderAdvancesBar(t *testing.T) {
+	content := strings.Repeat(test.RandomString(), 100)
+	bar := newTestBar(int64(len(content)))
+	out := new(bytes.Buffer)
+
+	n, err := io.Copy(out, NewProgressReader(io.LimitReader(strings.NewReader(content), int64(len(content))), bar))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, out.String())
+	assert.Equal(t, int64(len(content)), bar.Current())
+	assert.True(t, bar.IsDone())
+}
+
+func TestProgressReaderCompletesBarOnEOF(t *testing.T) {
+	content := test.RandomString()
+	bar := newTestBar(int64(len(content) * 2))
+
+	_, err := io.ReadAll(NewProgressReader(io.LimitReader(strings.NewReader(content), 1<<20), bar))
+
+	assert.NoError(t, err)
+	assert.True(t, bar.IsDone())
+	assert.NoError(t, bar.Err())
+}
+
+func TestProgressReaderFailsBarOnError(t *testing.T) {
+	expectedErr := errors.New(test.RandomString())
+	bar := newTestBar(100)
+
+	_, err := io.ReadAll(NewProgressReader(&failingReader{err: expectedErr}, bar))
+
+	assert.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, bar.Err(), expectedErr)
+	assert.False(t, bar.IsDone())
+	assert.False(t, bar.Tick(), "a failed bar is not expected to accept progress")
+}
+
+func TestProgressReaderInterfacePassthrough(t *testing.T) {
+	t.Run("ReaderAtAndSeeker", func(t *testing.T) {
+		r := NewProgressReader(strings.NewReader("content"), newTestBar(7))
+
+		_, isReaderAt := r.(io.ReaderAt)
+		_, isSeeker := r.(io.Seeker)
+		assert.True(t, isReaderAt)
+		assert.True(t, isSeeker)
+	})
+
+	t.Run("ReaderAtOnly", func(t *testing.T) {
+		underlying := strings.NewReader("content")
+		r := NewProgressReader(struct {
+			io.Reader
+			io.ReaderAt
+		}{underlying, underlying}, newTestBar(7))
+
+		_, isReaderAt := r.(io.ReaderAt)
+		_, isSeeker := r.(io.Seeker)
+		assert.True(t, isReaderAt)
+		assert.False(t, isSeeker)
+	})
+
+	t.Run("SeekerOnly", func(t *testing.T) {
+		r := NewProgressReader(struct{ io.ReadSeeker }{strings.NewReader("content")}, newTestBar(7))
+
+		_, isReaderAt := r.(io.ReaderAt)
+		_, isSeeker := r.(io.Seeker)
+		assert.False(t, isReaderAt)
+		assert.True(t, isSeeker)
+	})
+
+	t.Run("PlainReader", func(t *testing.T) {
+		r := NewProgressReader(io.LimitReader(strings.NewReader("content"), 7), newTestBar(7))
+
+		_, isReaderAt := r.(io.ReaderAt)
+		_, isSeeker := r.(io.Seeker)
+		assert.False(t, isReaderAt)
+		assert.False(t, isSeeker)
+	})
+}
+
+func TestProgressReaderSeekMovesProgress(t *testing.T) {
+	bar := newTestBar(10)
+	r := NewProgressReader(strings.NewReader("0123456789"), bar)
+
+	pos, err := r.(io.Seeker).Seek(6, io.SeekStart)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), pos)
+	assert.Equal(t, int64(6), bar.Current())
+
+	buf := make([]byte, 3)
+	n, err := r.(io.ReaderAt).ReadAt(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "012", string(buf[:n]))
+	assert.Equal(t, int64(6), bar.Current())
+}
+
+func TestProgressReaderSizeProbeKeepsProgress(t *testing.T) {
+	bar := newTestBar(10)
+	r := NewProgressReader(strings.NewReader("0123456789"), bar)
+	seeker := r.(io.Seeker)
+
+	size, err := seeker.Seek(0, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), size)
+	assert.Equal(t, int64(0), bar.Current())
+	assert.False(t, bar.IsDone())
+
+	_, err = seeker.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), bar.Current())
+
+	_, err = seeker.Seek(4, io.SeekCurrent)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), bar.Current())
+}
+
+func TestProgressWriterAdvancesBar(t *testing.T) {
+	content := test.RandomString()
+	bar := newTestBar(int64(len(content)))
+	out := new(bytes.Buffer)
+
+	n, err := io.Copy(NewProgressWriter(out, bar), strings.NewReader(content))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, out.String())
+	assert.True(t, bar.IsDone())
+}
+
+func TestProgressWriterFailsBarOnError(t *testing.T) {
+	expectedErr := errors.New(test.RandomString())
+	bar := newTestBar(100)
+
+	_, err := NewProgressWriter(&failingWriter{err: expectedErr}, bar).Write([]byte("data"))
+
+	assert.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, bar.Err(), expectedErr)
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func newTestBar(total int64) ExtendedProgressBar {
+	return NewProgressBarBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTotal(total).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		Build()
+}
@@ -0,0 +1,157 @@
+package termite
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// StackedSegmentSucceeded the index of the succeeded segment of a default stacked progress bar
+	StackedSegmentSucceeded = iota
+	// StackedSegmentFailed the index of the failed segment of a default stacked progress bar
+	StackedSegmentFailed
+	// StackedSegmentSkipped the index of the skipped segment of a default stacked progress bar
+	StackedSegmentSkipped
+
+	// DefaultStackedProgressBarTemplate the layout template of a default stacked progress bar
+	DefaultStackedProgressBarTemplate = "{msg} {bar} {percent} ({succeeded} succeeded, {failed} failed, {skipped} skipped)"
+)
+
+// DefaultStackedProgressBarSegments returns the segment names of a default stacked progress bar.
+func DefaultStackedProgressBarSegments() []string {
+	return []string{"succeeded", "failed", "skipped"}
+}
+
+// DefaultStackedProgressBarFormatter returns a formatter that fills the succeeded, failed and skipped segments
+// of a default stacked progress bar in green, red and yellow respectively.
+func DefaultStackedProgressBarFormatter() *SimpleProgressBarFormatter {
+	formatter := DefaultProgressBarFormatter()
+	formatter.Template = DefaultStackedProgressBarTemplate
+	formatter.SegmentFills = []string{
+		color.GreenString("%c", DefaultProgressBarFill),
+		color.RedString("%c", DefaultProgressBarFill),
+		color.YellowString("%c", DefaultProgressBarFill),
+	}
+
+	return formatter
+}
+
+// ProgressBarSegmentFormatter an optional extension of ProgressBarFormatter that styles the segments
+// of a StackedProgressBar.
+type ProgressBarSegmentFormatter interface {
+	// FormatSegmentFill returns a string that contains one visible character and optionally additional styling
+	// characters, to fill the specified segment with.
+	FormatSegmentFill(segment int) string
+}
+
+// FormatSegmentFill returns the fill of the specified segment or the fill char if none is set
+func (f *SimpleProgressBarFormatter) FormatSegmentFill(segment int) string {
+	if segment < len(f.SegmentFills) {
+		return f.SegmentFills[segment]
+	}
+	return f.FormatFill()
+}
+
+// StackedProgressBar an ExtendedProgressBar that tracks several categories of progress, such as succeeded, failed and skipped
+// items. Each category has its own counter and is rendered proportionally in its own segment within the same bar.
+//
+// The progress of a stacked bar is the sum of its segments. Tick, Add and SetCurrent apply to the first segment, which
+// SetCurrent never brings below zero.
+// Each segment count is available to the layout template as a token named after the segment, such as {failed}.
+type StackedProgressBar interface {
+	ExtendedProgressBar
+
+	// AddTo increments the progress of the specified segment by n and returns whether or not the bar is still in progress.
+	AddTo(segment int, n int64) bool
+
+	// SegmentCurrent returns the current progress of the specified segment.
+	SegmentCurrent(segment int) int64
+}
+
+// NewStackedProgressBar creates a new stacked progress bar with the specified segments
+// writer 					- the writer to use for output
+// total 						- the sum of all segments that is to be considered 100% of the progress
+// terminalWidthFn	- a function that returns the current terminal width
+// width 						- bar width in characters
+// formatter 		  	- a formatter for this progress bar
+// segments					- the segment names in order of appearance, defaults to DefaultStackedProgressBarSegments
+func NewStackedProgressBar(writer io.Writer, total int64, terminalWidthFn func() int, width int, formatter ProgressBarFormatter, segments ...string) StackedProgressBar {
+	return newStackedBar(newBar(writer, total, terminalWidthFn, width, formatter, DefaultProgressBarRefreshInterval), segments)
+}
+
+// NewDefaultStackedProgressBar creates a stacked progress bar with succeeded, failed and skipped segments and styling
+func NewDefaultStackedProgressBar(writer io.Writer, total int64, terminalWidthFn func() int) StackedProgressBar {
+	return NewStackedProgressBar(
+		writer, total, terminalWidthFn, terminalWidthFn()/2, DefaultStackedProgressBarFormatter(), DefaultStackedProgressBarSegments()...,
+	)
+}
+
+func newStackedBar(b *bar, segments []string) *bar {
+	if len(segments) == 0 {
+		segments = DefaultStackedProgressBarSegments()
+	}
+	b.segments = make([]atomic.Int64, len(segments))
+	b.segmentNames = segments
+
+	return b
+}
+
+// AddTo increments the progress of the specified segment by n. Does not imply visual change.
+func (b *bar) AddTo(segment int, n int64) bool {
+	if segment < 0 || segment >= len(b.segments) {
+		return false
+	}
+
+	return b.addTo(segment, n, *b.message.Load())
+}
+
+// SegmentCurrent returns the current progress of the specified segment
+func (b *bar) SegmentCurrent(segment int) int64 {
+	if segment < 0 || segment >= len(b.segments) {
+		return 0
+	}
+
+	return b.segments[segment].Load()
+}
+
+// renderStackedFill returns the segments of a stacked bar, each proportional to its share of the total.
+// Segment boundaries are rounded cumulatively so rounding errors don't add up.
+func (b *bar) renderStackedFill(totalChars int) string {
+	total := b.total.Load()
+	var sb strings.Builder
+	var cumulative int64
+	filledChars := 0
+
+	for i := range b.segments {
+		cumulative += b.segments[i].Load()
+		chars := totalChars
+		if total > 0 {
+			chars = int(min(1, float64(cumulative)/float64(total)) * float64(totalChars))
+		}
+		chars = max(0, chars-filledChars)
+
+		fill := b.formatter.FormatFill()
+		if formatter, ok := b.formatter.(ProgressBarSegmentFormatter); ok {
+			fill = formatter.FormatSegmentFill(i)
+		}
+		sb.WriteString(strings.Repeat(fill, chars))
+		filledChars += chars
+	}
+	sb.WriteString(strings.Repeat(b.formatter.FormatBlank(), totalChars-filledChars))
+
+	return sb.String()
+}
+
+// renderSegmentTokens returns template replacement pairs for the segment count tokens.
+func (b *bar) renderSegmentTokens() []string {
+	tokens := make([]string, 0, len(b.segments)*2)
+	for i, name := range b.segmentNames {
+		tokens = append(tokens, "{"+name+"}", strconv.FormatInt(b.segments[i].Load(), 10))
+	}
+
+	return tokens
+}
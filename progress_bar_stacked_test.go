@@ -0,0 +1,111 @@
+package termite
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackedProgressBarSegmentCounts(t *testing.T) {
+	pb := NewDefaultStackedProgressBar(new(bytes.Buffer), 10, fakeTerminalWidthFn)
+
+	assert.True(t, pb.Tick())
+	assert.True(t, pb.AddTo(StackedSegmentSucceeded, 4))
+	assert.True(t, pb.AddTo(StackedSegmentFailed, 2))
+	assert.True(t, pb.AddTo(StackedSegmentSkipped, 1))
+
+	assert.Equal(t, int64(5), pb.SegmentCurrent(StackedSegmentSucceeded))
+	assert.Equal(t, int64(2), pb.SegmentCurrent(StackedSegmentFailed))
+	assert.Equal(t, int64(1), pb.SegmentCurrent(StackedSegmentSkipped))
+	assert.Equal(t, int64(8), pb.Current())
+
+	assert.False(t, pb.AddTo(StackedSegmentFailed, 2))
+	assert.True(t, pb.IsDone())
+	assert.False(t, pb.AddTo(StackedSegmentSkipped, 1))
+}
+
+func TestStackedProgressBarInvalidSegment(t *testing.T) {
+	pb := NewDefaultStackedProgressBar(new(bytes.Buffer), 10, fakeTerminalWidthFn)
+
+	assert.False(t, pb.AddTo(-1, 1))
+	assert.False(t, pb.AddTo(3, 1))
+	assert.Equal(t, int64(0), pb.SegmentCurrent(3))
+	assert.Equal(t, int64(0), pb.Current())
+}
+
+func TestStackedProgressBarRendering(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := &SimpleProgressBarFormatter{
+		LeftBorderChar:  '[',
+		RightBorderChar: ']',
+		BlankChar:       ' ',
+		SegmentFills:    []string{"+", "x", "~"},
+		Template:        "{bar} {percent} {succeeded}/{failed}/{skipped}",
+	}
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(30).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithWidth(10).
+		WithFormatter(formatter).
+		WithRefreshInterval(0).
+		BuildStacked()
+
+	pb.AddTo(StackedSegmentSucceeded, 10)
+	pb.AddTo(StackedSegmentFailed, 5)
+	pb.AddTo(StackedSegmentSkipped, 4)
+
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "[+++xx~    ] 63% 10/5/4"))
+}
+
+func TestStackedProgressBarCumulativeRounding(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := &SimpleProgressBarFormatter{LeftBorderChar: '|', RightBorderChar: '|', BlankChar: ' ', SegmentFills: []string{"a", "b", "c"}, Template: "{bar}"}
+	pb := newStackedBar(newBar(emulatedStdout, 3, fakeTerminalWidthFn, 10, formatter, 0), []string{"a", "b", "c"})
+
+	pb.AddTo(0, 1)
+	pb.AddTo(1, 1)
+	pb.AddTo(2, 1)
+
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "|aaabbbcccc|"), "expected the full width to be used")
+}
+
+func TestStackedProgressBarSetCurrentAppliesToFirstSegment(t *testing.T) {
+	pb := NewDefaultStackedProgressBar(new(bytes.Buffer), 10, fakeTerminalWidthFn)
+
+	pb.AddTo(StackedSegmentFailed, 2)
+	pb.SetCurrent(7)
+
+	assert.Equal(t, int64(5), pb.SegmentCurrent(StackedSegmentSucceeded))
+	assert.Equal(t, int64(2), pb.SegmentCurrent(StackedSegmentFailed))
+
+	pb.AddTo(StackedSegmentFailed, 1)
+	pb.SetCurrent(1)
+
+	assert.Equal(t, int64(0), pb.SegmentCurrent(StackedSegmentSucceeded))
+	assert.Equal(t, int64(3), pb.SegmentCurrent(StackedSegmentFailed))
+	assert.Equal(t, int64(3), pb.Current())
+}
+
+func TestStackedProgressBarFinishKeepsSegmentProportions(t *testing.T) {
+	pb := NewDefaultStackedProgressBar(new(bytes.Buffer), 10, fakeTerminalWidthFn)
+
+	pb.AddTo(StackedSegmentSucceeded, 3)
+	pb.AddTo(StackedSegmentFailed, 1)
+	pb.Finish("")
+
+	assert.True(t, pb.IsDone())
+	assert.Equal(t, int64(4), pb.Total())
+	assert.Equal(t, int64(3), pb.SegmentCurrent(StackedSegmentSucceeded))
+}
+
+func TestDefaultStackedProgressBarTemplate(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewDefaultStackedProgressBar(emulatedStdout, 10, fakeTerminalWidthFn)
+
+	pb.AddTo(StackedSegmentSkipped, 1)
+
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "10% (0 succeeded, 0 failed, 1 skipped)"))
+}
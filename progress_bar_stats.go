@@ -0,0 +1,100 @@
+package termite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// rateSampleInterval the minimal time between two samples taken by a rateEstimator.
+	rateSampleInterval = time.Millisecond * 100
+
+	// rateSmoothingWindow the time constant of the exponentially weighted moving average used by rateEstimator.
+	rateSmoothingWindow = time.Second * 3
+)
+
+// rateEstimator estimates throughput using an exponentially weighted moving average (EWMA) over time,
+// so bursty progress does not make the estimate jump around.
+type rateEstimator struct {
+	rate       float64
+	lastValue  int64
+	lastSample time.Time
+	sampled    bool
+}
+
+// reset restarts the estimation from the specified value and time.
+func (e *rateEstimator) reset(value int64, now time.Time) {
+	e.rate = 0
+	e.lastValue = value
+	e.lastSample = now
+	e.sampled = false
+}
+
+// update adds a sample to the estimator and returns the current rate estimation in units per second.
+func (e *rateEstimator) update(value int64, now time.Time) float64 {
+	dt := now.Sub(e.lastSample)
+	if dt < rateSampleInterval {
+		return e.rate
+	}
+
+	instantRate := float64(value-e.lastValue) / dt.Seconds()
+	if e.sampled {
+		alpha := 1 - math.Exp(-dt.Seconds()/rateSmoothingWindow.Seconds())
+		e.rate = alpha*instantRate + (1-alpha)*e.rate
+	} else {
+		e.rate = instantRate
+		e.sampled = true
+	}
+
+	e.lastValue = value
+	e.lastSample = now
+
+	return e.rate
+}
+
+// eta returns the estimated time remaining for the specified remaining amount, or a negative duration if it cannot
+// be estimated yet.
+func (e *rateEstimator) eta(remaining int64) time.Duration {
+	if remaining <= 0 {
+		return 0
+	}
+	if e.rate <= 0 {
+		return -1
+	}
+
+	return time.Duration(float64(remaining) / e.rate * float64(time.Second))
+}
+
+// formatDuration formats a duration in a compact human readable form, such as 1h02m, 1m12s or 7s.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm%02ds", m, s)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
+
+// formatBytes formats a number of bytes using binary unit prefixes, such as 1.5 KiB or 12.0 MiB.
+func formatBytes(n float64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%.0f B", n)
+	}
+
+	div, exp := float64(unit), 0
+	for v := n / unit; v >= unit && exp < 5; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", n/div, "KMGTPE"[exp])
+}
@@ -0,0 +1,75 @@
+package termite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateEstimatorSmoothsBurstyProgress(t *testing.T) {
+	estimator := &rateEstimator{}
+	now := time.Now()
+	estimator.reset(0, now)
+
+	// steady 100 units per second
+	var value int64
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Millisecond * 100)
+		value += 10
+		estimator.update(value, now)
+	}
+	assert.InDelta(t, 100, estimator.rate, 1)
+
+	// a single burst of 1000 units in 100ms
+	now = now.Add(time.Millisecond * 100)
+	value += 1000
+	rate := estimator.update(value, now)
+
+	assert.Less(t, rate, float64(1000))
+	assert.Greater(t, rate, float64(100))
+}
+
+func TestRateEstimatorIgnoresSamplesWithinSampleInterval(t *testing.T) {
+	estimator := &rateEstimator{}
+	now := time.Now()
+	estimator.reset(0, now)
+
+	assert.Equal(t, float64(0), estimator.update(1000, now.Add(time.Millisecond)))
+	assert.InDelta(t, 1000, estimator.update(1000, now.Add(time.Second)), 0.001)
+}
+
+func TestRateEstimatorETA(t *testing.T) {
+	estimator := &rateEstimator{}
+	now := time.Now()
+	estimator.reset(0, now)
+
+	assert.Less(t, estimator.eta(100), time.Duration(0), "no rate yet")
+	assert.Equal(t, time.Duration(0), estimator.eta(0))
+
+	estimator.update(10, now.Add(time.Second))
+	assert.Equal(t, time.Second*9, estimator.eta(90))
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "0s"},
+		{d: time.Millisecond * 1600, want: "2s"},
+		{d: time.Minute + time.Second*12, want: "1m12s"},
+		{d: time.Hour + time.Minute*2 + time.Second, want: "1h02m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatDuration(tt.d))
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "3.0 GiB", formatBytes(3*1024*1024*1024))
+}
@@ -3,9 +3,17 @@ package termite
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	stdio "io"
 	"math/rand"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/sha1n/gommons/pkg/io"
 	"github.com/sha1n/gommons/pkg/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -142,3 +150,576 @@ func TestProgressBarIsDoneEdgeCases(t *testing.T) {
 		assert.True(t, bar.IsDone())
 	})
 }
+
+func TestProgressBarAddWithInt64Total(t *testing.T) {
+	const gb = int64(1) << 30
+	bar := NewProgressBarBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTotal(4 * gb).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		Build()
+
+	assert.True(t, bar.Add(gb))
+	assert.True(t, bar.Add(2*gb))
+	assert.Equal(t, 3*gb, bar.Current())
+	assert.False(t, bar.Add(gb))
+	assert.True(t, bar.IsDone())
+	assert.False(t, bar.Add(gb))
+	assert.Equal(t, 4*gb, bar.Current())
+}
+
+func TestProgressBarSetCurrent(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	bar := NewProgressBar(emulatedStdout, 10, fakeTerminalWidthFn, 50, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	assert.True(t, bar.SetCurrent(5))
+	assert.Contains(t, emulatedStdout.String(), "50%")
+	assert.True(t, bar.SetCurrent(2))
+	assert.Equal(t, int64(2), bar.Current())
+	assert.False(t, bar.SetCurrent(10))
+	assert.True(t, bar.IsDone())
+}
+
+func TestProgressBarSetTotal(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	bar := NewProgressBar(emulatedStdout, 2, fakeTerminalWidthFn, 50, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	assert.True(t, bar.Tick())
+	bar.SetTotal(4)
+	assert.Equal(t, int64(4), bar.Total())
+	assert.Contains(t, emulatedStdout.String(), "25%")
+	assert.True(t, bar.Tick())
+	assert.True(t, bar.Tick())
+	assert.False(t, bar.Tick())
+	assert.True(t, bar.IsDone())
+}
+
+func TestProgressBarBuilder(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := DefaultProgressBarFormatterWidth(10)
+
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(123).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithWidth(42).
+		WithFormatter(formatter).
+		Build()
+
+	b := pb.(*bar)
+	assert.Equal(t, emulatedStdout, b.writer)
+	assert.Equal(t, int64(123), b.Total())
+	assert.Equal(t, 42, b.calculateWidth(0))
+	assert.Equal(t, formatter, b.formatter)
+}
+
+func TestProgressBarStatsSegments(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := DefaultProgressBarFormatter()
+	formatter.ShowElapsed = true
+	formatter.ShowETA = true
+	formatter.ShowRate = true
+	formatter.RateUnit = "files"
+
+	pb := NewProgressBar(emulatedStdout, 100, fakeTerminalWidthFn, 50, formatter).(*bar)
+	clock := pb.startTime
+	pb.now = func() time.Time { return clock }
+
+	clock = clock.Add(time.Second * 2)
+	assert.True(t, pb.Add(20))
+
+	output := emulatedStdout.String()
+	assert.Contains(t, output, "20% 2s ETA 8s 10.0 files/s")
+}
+
+func TestProgressBarStatsSegmentsReduceBarWidth(t *testing.T) {
+	withStats := DefaultProgressBarFormatter()
+	withStats.ShowETA = true
+	plainStdout, reducedStdout := new(bytes.Buffer), new(bytes.Buffer)
+
+	NewProgressBar(plainStdout, 10, fakeTerminalWidthFn, fakeTerminalWidth, DefaultProgressBarFormatter()).Tick()
+	NewProgressBar(reducedStdout, 10, fakeTerminalWidthFn, fakeTerminalWidth, withStats).Tick()
+
+	assert.Equal(t, barCharsIn(plainStdout.String())-len(" ETA --"), barCharsIn(reducedStdout.String()))
+	assert.Equal(t, displayWidth(plainStdout.String()), displayWidth(reducedStdout.String()))
+}
+
+func TestProgressBarStatsHiddenByDefault(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	bar := NewDefaultProgressBar(emulatedStdout, 10, fakeTerminalWidthFn)
+
+	assert.True(t, bar.Tick())
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "10%"))
+}
+
+func TestSimpleProgressBarFormatterByteRate(t *testing.T) {
+	formatter := &SimpleProgressBarFormatter{ShowRate: true, ByteRate: true}
+
+	assert.Equal(t, "2.0 MiB/s", formatter.FormatRate(2*1024*1024))
+}
+
+func TestIndeterminateProgressBar(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(ProgressBarUnknownTotal).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithWidth(22).
+		WithRefreshInterval(0).
+		Build()
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, pb.Tick())
+	}
+
+	assert.False(t, pb.IsDone())
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), " 100"), "a counter is expected instead of a percentage")
+	assert.NotContains(t, emulatedStdout.String(), "%")
+}
+
+func TestIndeterminateProgressBarAnimation(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := &SimpleProgressBarFormatter{LeftBorderChar: '[', RightBorderChar: ']', FillChar: '=', BlankChar: ' '}
+	pb := newBar(emulatedStdout, 0, fakeTerminalWidthFn, 10, formatter, 0)
+	clock := pb.startTime
+	pb.now = func() time.Time { return clock }
+	pb.SetTotal(ProgressBarUnknownTotal)
+
+	frameAt := func(frame int) string {
+		clock = pb.startTime.Add(indeterminateFrameInterval * time.Duration(frame))
+		emulatedStdout.Reset()
+		pb.Add(1)
+		return emulatedStdout.String()
+	}
+
+	assert.Contains(t, frameAt(0), "[==        ]")
+	assert.Contains(t, frameAt(3), "[   ==     ]")
+	assert.Contains(t, frameAt(8), "[        ==]")
+	assert.Contains(t, frameAt(11), "[     ==   ]", "expected the block to bounce back")
+	assert.Contains(t, frameAt(16), "[==        ]")
+}
+
+func TestIndeterminateProgressBarSwitchesToDeterminate(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(ProgressBarUnknownTotal).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		Build()
+
+	assert.True(t, pb.Add(30))
+	pb.SetTotal(60)
+
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), " 50%"))
+	assert.False(t, pb.Add(30))
+	assert.True(t, pb.IsDone())
+}
+
+func TestIndeterminateProgressBarAnimatesInBackground(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	pb := NewProgressBar(emulatedStdout, int(ProgressBarUnknownTotal), fakeTerminalWidthFn, 50, DefaultProgressBarFormatter())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := pb.Start(ctx)
+	assert.NoError(t, err)
+
+	rendered := emulatedStdout.Len()
+	assert.Eventually(t, func() bool {
+		return emulatedStdout.Len() > rendered
+	}, timeout, indeterminateFrameInterval)
+}
+
+func TestProgressBarPartialFill(t *testing.T) {
+	formatter := &SimpleProgressBarFormatter{
+		LeftBorderChar:   '[',
+		RightBorderChar:  ']',
+		FillChar:         DefaultProgressBarFill,
+		BlankChar:        ' ',
+		PartialFillChars: DefaultProgressBarPartialFillChars(),
+	}
+
+	tests := []struct {
+		current int64
+		want    string
+	}{
+		{current: 0, want: "[    ]"},
+		{current: 1, want: "[▏   ]"},
+		{current: 4, want: "[▌   ]"},
+		{current: 8, want: "[█   ]"},
+		{current: 13, want: "[█▋  ]"},
+		{current: 31, want: "[███▉]"},
+		{current: 32, want: "[████]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			emulatedStdout := new(bytes.Buffer)
+			pb := NewProgressBar(emulatedStdout, 32, fakeTerminalWidthFn, 4, formatter).(ExtendedProgressBar)
+
+			pb.SetCurrent(tt.current)
+
+			assert.Contains(t, emulatedStdout.String(), tt.want)
+		})
+	}
+}
+
+func TestProgressBarPartialFillDisabledByDefault(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewProgressBar(emulatedStdout, 32, fakeTerminalWidthFn, 4, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	pb.SetCurrent(13)
+
+	assert.Contains(t, emulatedStdout.String(), "▏█░░░▕")
+}
+
+func barCharsIn(s string) int {
+	return strings.Count(s, string(DefaultProgressBarFill)) + strings.Count(s, string(DefaultProgressBarBlank))
+}
+
+func TestProgressBarTemplate(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := &SimpleProgressBarFormatter{
+		LeftBorderChar:  '[',
+		RightBorderChar: ']',
+		FillChar:        '#',
+		BlankChar:       '-',
+		Template:        "{percent} {bar} {current}/{total} {msg}",
+		MessageWidth:    5,
+	}
+	pb := newBar(emulatedStdout, 200, fakeTerminalWidthFn, 10, formatter, 0)
+
+	pb.SetCurrent(74)
+	pb.TickMessage("label")
+
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"37% [###-------] 75/200 label"))
+}
+
+func TestProgressBarTemplateFillsTerminalWidth(t *testing.T) {
+	templates := []string{
+		DefaultProgressBarTemplate,
+		"{msg} {bar}",
+		"{bar} {current}/{total} {percent}",
+		"[{msg}] {percent} {bar} {eta}",
+	}
+	for _, template := range templates {
+		t.Run(template, func(t *testing.T) {
+			emulatedStdout := new(bytes.Buffer)
+			formatter := DefaultProgressBarFormatterWidth(10)
+			formatter.Template = template
+			pb := NewProgressBar(emulatedStdout, 100, fakeTerminalWidthFn, fakeTerminalWidth*2, formatter).(ExtendedProgressBar)
+
+			pb.SetCurrent(100)
+
+			assert.Equal(t, fakeTerminalWidth, displayWidth(emulatedStdout.String()))
+		})
+	}
+}
+
+func TestProgressBarTemplateStatsTokens(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewProgressBar(emulatedStdout, 100, fakeTerminalWidthFn, 10, &customTemplateFormatter{
+		ProgressBarFormatter: DefaultProgressBarFormatter(),
+		template:             "{bar} {elapsed} {eta} {rate}",
+	}).(*bar)
+	clock := pb.startTime
+	pb.now = func() time.Time { return clock }
+
+	clock = clock.Add(time.Second)
+	pb.Add(25)
+
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), " 1s ETA 3s 25.0 it/s"))
+}
+
+func TestProgressBarTemplateIndeterminateTotal(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := DefaultProgressBarFormatter()
+	formatter.Template = "{current}/{total}"
+	pb := NewProgressBar(emulatedStdout, int(ProgressBarUnknownTotal), fakeTerminalWidthFn, 10, formatter).(ExtendedProgressBar)
+
+	pb.Add(42)
+
+	assert.Equal(t, TermControlEraseLine+"42/?", emulatedStdout.String())
+}
+
+// customTemplateFormatter a formatter that implements ProgressBarTemplateFormatter but not ProgressBarStatsFormatter
+type customTemplateFormatter struct {
+	ProgressBarFormatter
+	template string
+}
+
+func (f *customTemplateFormatter) LayoutTemplate() string {
+	return f.template
+}
+
+func TestProgressBarFinish(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	expectedMessage := test.RandomString()
+	pb := NewProgressBar(emulatedStdout, 10, fakeTerminalWidthFn, 10, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	pb.Add(3)
+	emulatedStdout.Reset()
+	pb.Finish(expectedMessage)
+
+	assert.Equal(t, ProgressBarFinished, pb.State())
+	assert.True(t, pb.IsDone())
+	assert.Equal(t, int64(10), pb.Current())
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "100% "+expectedMessage))
+	assert.Equal(t, 10, strings.Count(emulatedStdout.String(), string(DefaultProgressBarFill)))
+	assert.False(t, pb.Tick())
+}
+
+func TestProgressBarFinishIndeterminate(t *testing.T) {
+	pb := NewProgressBar(new(bytes.Buffer), int(ProgressBarUnknownTotal), fakeTerminalWidthFn, 10, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	pb.Add(42)
+	pb.Finish("")
+
+	assert.True(t, pb.IsDone())
+	assert.Equal(t, int64(42), pb.Total())
+}
+
+func TestProgressBarFail(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	expectedErr := errors.New(test.RandomString())
+	pb := NewProgressBar(emulatedStdout, 10, fakeTerminalWidthFn, 10, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	pb.Add(3)
+	emulatedStdout.Reset()
+	pb.Fail(expectedErr)
+
+	assert.Equal(t, ProgressBarFailed, pb.State())
+	assert.Equal(t, expectedErr, pb.Err())
+	assert.False(t, pb.IsDone())
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "30% "+expectedErr.Error()))
+	assert.Equal(t, 3, strings.Count(emulatedStdout.String(), string(DefaultProgressBarFill)))
+	assert.False(t, pb.Tick())
+	assert.False(t, pb.SetCurrent(5))
+}
+
+func TestProgressBarAbort(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewProgressBar(emulatedStdout, 10, fakeTerminalWidthFn, 10, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	pb.Add(3)
+	emulatedStdout.Reset()
+	pb.Abort()
+
+	assert.Equal(t, ProgressBarAborted, pb.State())
+	assert.Equal(t, TermControlEraseLine, emulatedStdout.String())
+	assert.False(t, pb.Tick())
+}
+
+func TestProgressBarEndsOnlyOnce(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	pb := NewProgressBar(emulatedStdout, 10, fakeTerminalWidthFn, 10, DefaultProgressBarFormatter()).(ExtendedProgressBar)
+
+	pb.Finish("")
+	emulatedStdout.Reset()
+	pb.Fail(errors.New("too late"))
+	pb.Abort()
+
+	assert.Equal(t, ProgressBarFinished, pb.State())
+	assert.NoError(t, pb.Err())
+	assert.Empty(t, emulatedStdout.String())
+}
+
+func TestProgressBarCompletionFormatter(t *testing.T) {
+	formatter := &SimpleProgressBarFormatter{FillChar: '#'}
+
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	assert.Equal(t, color.GreenString("#"), formatter.FormatFinalFill(ProgressBarFinished))
+	assert.Equal(t, color.RedString("#"), formatter.FormatFinalFill(ProgressBarFailed))
+	assert.Equal(t, color.RedString("100%"), formatter.FormatFinalMessage(ProgressBarFailed, "100%"))
+	assert.Equal(t, "done", formatter.FormatFinalMessage(ProgressBarFinished, "done"))
+}
+
+func TestStartedProgressBarCoalescesRenders(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	ticks := 100000
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(int64(ticks)).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(time.Hour).
+		Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tick, err := pb.Start(ctx)
+	assert.NoError(t, err)
+
+	for i := 1; i < ticks; i++ {
+		assert.True(t, tick(""))
+	}
+	assert.Equal(t, 1, strings.Count(emulatedStdout.String(), TermControlEraseLine), "only the initial render is expected")
+
+	assert.False(t, tick(""))
+	assert.Eventually(t, bufferContains(emulatedStdout, "100%"), timeout, time.Millisecond)
+	assert.Equal(t, 2, strings.Count(emulatedStdout.String(), TermControlEraseLine), "the completion is expected to render immediately")
+}
+
+func TestStartedProgressBarRendersEveryRefreshInterval(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(10).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(time.Millisecond).
+		Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := pb.Start(ctx)
+	assert.NoError(t, err)
+
+	pb.Add(3)
+	assert.Eventually(t, bufferContains(emulatedStdout, "30%"), timeout, time.Millisecond)
+}
+
+func TestStartedProgressBarConcurrentTicks(t *testing.T) {
+	goroutines, ticksPerGoroutine := 50, 1000
+	pb := NewProgressBarBuilder().
+		WithWriter(io.NewUnlimitedProbedWriter(new(bytes.Buffer))).
+		WithTotal(int64(goroutines * ticksPerGoroutine)).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(time.Millisecond).
+		Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tick, _ := pb.Start(ctx)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Go(func() {
+			for j := 0; j < ticksPerGoroutine; j++ {
+				tick("")
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.True(t, pb.IsDone())
+	assert.Equal(t, int64(goroutines*ticksPerGoroutine), pb.Current())
+}
+
+func TestStartedProgressBarStopsRenderingWhenContextEnds(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(ProgressBarUnknownTotal).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(time.Millisecond).
+		Build()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tick, _ := pb.Start(ctx)
+	assert.True(t, tick(""))
+	cancel()
+
+	assert.False(t, tick(""), "a tick is expected to return immediately once the context has ended")
+	assert.Eventually(t, func() bool {
+		emulatedStdout.Reset()
+		time.Sleep(time.Millisecond * 10)
+		return emulatedStdout.Len() == 0
+	}, timeout, time.Millisecond, "expected no more output once the context has ended")
+}
+
+// TestLegacyProgressBarsRenderBeforeTicksReturn runs the cursor-driven pattern of the original demo, where several bars
+// share a writer and the cursor is moved between their ticks.
+func TestLegacyProgressBarsRenderBeforeTicksReturn(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	cursor := NewCursor(emulatedStdout)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := 10
+	tickFns := []TickMessageFn{}
+	for i := 0; i < 4; i++ {
+		bar := NewProgressBar(emulatedStdout, ticks, fakeTerminalWidthFn, 20, DefaultProgressBarFormatter())
+		tick, err := bar.Start(ctx)
+		assert.NoError(t, err)
+		tickFns = append(tickFns, tick)
+	}
+
+	for i := 1; i <= ticks; i++ {
+		for _, tick := range tickFns {
+			before := emulatedStdout.String()
+			tick("")
+			rendered := strings.TrimPrefix(emulatedStdout.String(), before)
+
+			assert.True(t, strings.HasPrefix(rendered, TermControlEraseLine), "expected the tick to render before returning")
+			assert.True(t, strings.HasSuffix(rendered, fmt.Sprintf("%d%%", i*100/ticks)))
+			cursor.Down(1)
+		}
+		cursor.Up(len(tickFns))
+	}
+}
+
+func TestProgressBarCoalescesRendersWhenNotStarted(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	ticks := 10000
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(int64(ticks)).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(time.Hour).
+		Build()
+
+	for pb.Tick() {
+	}
+
+	assert.Equal(t, 2, strings.Count(emulatedStdout.String(), TermControlEraseLine), "expected the first and the last ticks to render")
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "100%"))
+}
+
+func TestProgressBarRendersTrailingUpdateWhenNotStarted(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(10).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(50 * time.Millisecond).
+		Build()
+
+	assert.True(t, pb.Tick())
+	assert.True(t, pb.Tick())
+	assert.Equal(t, 1, strings.Count(emulatedStdout.String(), TermControlEraseLine), "only the first tick is expected to render")
+
+	assert.Eventually(t, bufferContains(emulatedStdout, "20%"), timeout, time.Millisecond)
+	assert.Equal(t, 2, strings.Count(emulatedStdout.String(), TermControlEraseLine))
+}
+
+func TestStartedProgressBarStopsRenderingWhenComplete(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	pb := NewProgressBarBuilder().
+		WithWriter(emulatedStdout).
+		WithTotal(2).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithRefreshInterval(time.Hour).
+		Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tick, err := pb.Start(ctx)
+	assert.NoError(t, err)
+	assert.True(t, tick(""))
+	assert.False(t, tick(""))
+
+	assert.Eventually(t, func() bool { return !pb.(*bar).background.Load() }, timeout, time.Millisecond)
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "100%"))
+}
+
+func BenchmarkStartedProgressBarTick(b *testing.B) {
+	pb := NewProgressBar(stdio.Discard, b.N+1, fakeTerminalWidthFn, 50, DefaultProgressBarFormatter())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tick, _ := pb.Start(ctx)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tick("")
+	}
+}
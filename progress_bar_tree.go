@@ -0,0 +1,139 @@
+package termite
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	// defaultProgressBarTotal the total of bars that don't specify one
+	defaultProgressBarTotal int64 = 100
+
+	// progressBarChildIndent the indentation of each child level relative to its parent
+	progressBarChildIndent = "  "
+)
+
+// NewChild creates a bar that contributes to the progress of this bar in proportion to its weight.
+//
+// The child inherits the formatter, width and refresh interval of its parent. When the writer is a MatrixRow, the row
+// of a collapsed child is hidden rather than cleared, so that it takes no line in the matrix. An indeterminate parent takes a total of
+// 100 once it has children, in which case its current value represents the aggregated percentage.
+func (b *bar) NewChild(writer io.Writer, total int64, weight float64) ExtendedProgressBar {
+	if writer == nil {
+		writer = io.Discard
+	}
+	if weight <= 0 {
+		weight = 1
+	}
+
+	child := newBar(writer, total, nil, 0, b.formatter, b.refreshInterval)
+	child.calculateWidth = func(fixedWidth int) int {
+		return max(0, b.calculateWidth(fixedWidth)-len(progressBarChildIndent))
+	}
+	child.now = func() time.Time { return b.now() }
+	child.parent = b
+	child.weight = weight
+	child.indent = b.indent + progressBarChildIndent
+	child.collapseFinishedChildren = b.collapseFinishedChildren
+
+	b.childrenMx.Lock()
+	b.children = append(b.children, child)
+	b.childrenMx.Unlock()
+
+	b.total.CompareAndSwap(ProgressBarUnknownTotal, defaultProgressBarTotal)
+	b.changed(true)
+
+	return child
+}
+
+// childChanged marks this bar for rendering following a change in one of its children.
+func (b *bar) childChanged() {
+	if b.State() != ProgressBarRunning {
+		return
+	}
+
+	b.changed(false)
+}
+
+// hasChildren returns whether or not the progress of this bar is derived from children.
+func (b *bar) hasChildren() bool {
+	b.childrenMx.RLock()
+	defer b.childrenMx.RUnlock()
+
+	return len(b.children) > 0
+}
+
+// childBars returns a snapshot of the children of this bar.
+func (b *bar) childBars() []*bar {
+	b.childrenMx.RLock()
+	defer b.childrenMx.RUnlock()
+
+	if len(b.children) == 0 {
+		return nil
+	}
+
+	return append([]*bar(nil), b.children...)
+}
+
+// isComplete returns whether or not this bar has either ended or reached 100%.
+func (b *bar) isComplete() bool {
+	return b.State() != ProgressBarRunning || b.IsDone()
+}
+
+// hideableWriter is implemented by writers whose line can be removed from the display, such as ExtendedMatrixRow.
+type hideableWriter interface {
+	SetHidden(bool)
+}
+
+// setHidden hides or shows the line of this bar if its writer supports it and returns whether it does.
+// Must be called while holding renderMx.
+func (b *bar) setHidden(hidden bool) bool {
+	writer, ok := b.writer.(hideableWriter)
+	if ok && hidden != b.hidden {
+		writer.SetHidden(hidden)
+		b.hidden = hidden
+	}
+
+	return ok
+}
+
+// isCollapsed returns whether or not this bar is a completed child that should no longer be displayed.
+// Failed children are never collapsed.
+func (b *bar) isCollapsed() bool {
+	if b.parent == nil || !b.parent.collapseFinishedChildren {
+		return false
+	}
+
+	state := b.State()
+	return state == ProgressBarFinished || (state == ProgressBarRunning && b.IsDone())
+}
+
+// contribution returns the completed fraction of this bar as seen by its parent.
+// An indeterminate bar doesn't contribute progress until it ends.
+func (b *bar) contribution() float64 {
+	if b.isIndeterminate() {
+		return 0
+	}
+
+	return b.fraction()
+}
+
+func weightedFraction(children []*bar) float64 {
+	var done, total float64
+	for _, child := range children {
+		done += child.weight * child.contribution()
+		total += child.weight
+	}
+
+	return done / total
+}
+
+func allChildrenComplete(children []*bar) bool {
+	for _, child := range children {
+		if !child.isComplete() {
+			return false
+		}
+	}
+
+	return true
+}
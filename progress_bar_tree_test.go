@@ -0,0 +1,192 @@
+package termite
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressBarChildrenWeightedProgress(t *testing.T) {
+	parent := newTestTreeBar(new(bytes.Buffer))
+	first := parent.NewChild(nil, 10, 1)
+	second := parent.NewChild(nil, 4, 3)
+
+	first.Add(5)
+	assert.Equal(t, int64(12), parent.Current())
+
+	second.Add(2)
+	assert.Equal(t, int64(50), parent.Current())
+	assert.False(t, parent.IsDone())
+
+	first.Add(5)
+	second.Add(2)
+	assert.Equal(t, int64(100), parent.Current())
+	assert.True(t, parent.IsDone())
+}
+
+func TestProgressBarChildrenNonPositiveWeight(t *testing.T) {
+	parent := newTestTreeBar(new(bytes.Buffer))
+	first := parent.NewChild(nil, 10, 0)
+	parent.NewChild(nil, 10, -5)
+
+	first.Add(10)
+
+	assert.Equal(t, int64(50), parent.Current())
+}
+
+func TestProgressBarChildrenDoneWhenAllEnded(t *testing.T) {
+	parent := newTestTreeBar(new(bytes.Buffer))
+	first := parent.NewChild(nil, 10, 1)
+	second := parent.NewChild(nil, 10, 1)
+	third := parent.NewChild(nil, ProgressBarUnknownTotal, 1)
+
+	first.Add(4)
+	first.Fail(errors.New("failed"))
+	second.Abort()
+	assert.False(t, parent.IsDone())
+
+	third.Add(7)
+	assert.Equal(t, int64(13), parent.Current())
+
+	third.Finish("")
+	assert.True(t, parent.IsDone())
+	assert.Equal(t, int64(46), parent.Current())
+}
+
+func TestProgressBarChildrenOfIndeterminateParent(t *testing.T) {
+	parent := NewProgressBarBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTotal(ProgressBarUnknownTotal).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		Build()
+
+	child := parent.NewChild(nil, 2, 1)
+	child.Tick()
+
+	assert.Equal(t, defaultProgressBarTotal, parent.Total())
+	assert.Equal(t, int64(50), parent.Current())
+}
+
+func TestProgressBarFinishedParentIsComplete(t *testing.T) {
+	parent := newTestTreeBar(new(bytes.Buffer))
+	parent.NewChild(nil, 10, 1)
+
+	parent.Finish("")
+
+	assert.Equal(t, int64(100), parent.Current())
+}
+
+func TestProgressBarChildrenRendering(t *testing.T) {
+	parentOut := new(bytes.Buffer)
+	childOut := new(bytes.Buffer)
+	grandchildOut := new(bytes.Buffer)
+	parent := newTestTreeBar(parentOut)
+	child := parent.NewChild(childOut, 4, 1)
+	grandchild := child.NewChild(grandchildOut, 2, 1)
+
+	grandchild.Tick()
+
+	assert.True(t, strings.HasSuffix(parentOut.String(), TermControlEraseLine+"[#####     ] 50%"))
+	assert.True(t, strings.HasSuffix(childOut.String(), TermControlEraseLine+"  [####    ] 50%"))
+	assert.True(t, strings.HasSuffix(grandchildOut.String(), TermControlEraseLine+"    [###   ] 50%"))
+}
+
+func TestProgressBarCollapseFinishedChildren(t *testing.T) {
+	doneOut := new(bytes.Buffer)
+	failedOut := new(bytes.Buffer)
+	parent := NewProgressBarBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithWidth(10).
+		WithFormatter(newTestTreeFormatter()).
+		WithRefreshInterval(0).
+		WithCollapseFinishedChildren(true).
+		Build()
+	done := parent.NewChild(doneOut, 2, 1)
+	failed := parent.NewChild(failedOut, 2, 1)
+
+	done.Tick()
+	done.Tick()
+	failed.Fail(errors.New("failed"))
+
+	assert.True(t, strings.HasSuffix(doneOut.String(), "50%"+TermControlEraseLine))
+	assert.True(t, strings.HasSuffix(failedOut.String(), "0% failed"))
+}
+
+func TestProgressBarCollapseFinishedChildrenInMatrix(t *testing.T) {
+	matrix := NewMatrixBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithTerminalHeightFn(func() int { return 0 }).
+		WithTerminalWidthFn(func() int { return 0 }).
+		Build()
+	parent := NewProgressBarBuilder().
+		WithWriter(matrix.NewRow()).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithWidth(10).
+		WithFormatter(newTestTreeFormatter()).
+		WithRefreshInterval(0).
+		WithCollapseFinishedChildren(true).
+		Build()
+	done := parent.NewChild(matrix.NewRow(), 2, 1)
+	running := parent.NewChild(matrix.NewRow(), 2, 1)
+	failed := parent.NewChild(matrix.NewRow(), 2, 1)
+
+	running.Tick()
+	done.Tick()
+	done.Tick()
+	failed.Fail(errors.New("failed"))
+
+	lines := matrix.(*matrixImpl).frameLines(true)
+	assert.Len(t, lines, 3, "expected the line of the finished child to be removed")
+	for _, line := range lines {
+		assert.NotEmpty(t, strings.TrimPrefix(line, TermControlEraseLine))
+	}
+	assert.Contains(t, lines[1], "50%")
+	assert.Contains(t, lines[2], "failed")
+}
+
+func TestProgressBarChildrenConcurrentUpdates(t *testing.T) {
+	parent := newTestTreeBar(new(bytes.Buffer))
+	children := make([]ExtendedProgressBar, 10)
+	for i := range children {
+		children[i] = parent.NewChild(new(bytes.Buffer), 100, float64(i+1))
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, child := range children {
+		wg.Add(1)
+		go func(child ExtendedProgressBar) {
+			defer wg.Done()
+			for child.Tick() {
+			}
+		}(child)
+	}
+	wg.Wait()
+
+	assert.True(t, parent.IsDone())
+	assert.Equal(t, int64(100), parent.Current())
+}
+
+func newTestTreeBar(writer *bytes.Buffer) ExtendedProgressBar {
+	return NewProgressBarBuilder().
+		WithWriter(writer).
+		WithTerminalWidthFn(fakeTerminalWidthFn).
+		WithWidth(10).
+		WithFormatter(newTestTreeFormatter()).
+		WithRefreshInterval(0).
+		Build()
+}
+
+func newTestTreeFormatter() *SimpleProgressBarFormatter {
+	return &SimpleProgressBarFormatter{
+		LeftBorderChar:  '[',
+		RightBorderChar: ']',
+		FillChar:        '#',
+		BlankChar:       ' ',
+		Template:        "{bar} {percent}",
+	}
+}
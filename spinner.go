@@ -1,6 +1,7 @@
 package termite
 
 import (
+	"bytes"
 	"container/ring"
 	"context"
 	"errors"
@@ -9,6 +10,33 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/fatih/color"
+)
+
+var (
+	// ErrSpinnerNotActive returned by operations that require a running spinner, once the spinner has stopped
+	ErrSpinnerNotActive = errors.New("spinner not active")
+
+	// ErrSpinnerAlreadyActive returned when starting a spinner that is already running
+	ErrSpinnerAlreadyActive = errors.New("spinner already active")
+)
+
+// defaultSpinnerInterval the interval of a spinner that is not given a positive one
+const defaultSpinnerInterval = time.Millisecond * 100
+
+// SpinnerStatus the final status a spinner can be stopped with.
+type SpinnerStatus int
+
+const (
+	// SpinnerStatusSuccess a spinner that has been stopped by Succeed
+	SpinnerStatusSuccess SpinnerStatus = iota
+	// SpinnerStatusFailure a spinner that has been stopped by Fail
+	SpinnerStatusFailure
+	// SpinnerStatusWarning a spinner that has been stopped by Warn
+	SpinnerStatusWarning
+	// SpinnerStatusInfo a spinner that has been stopped by Info
+	SpinnerStatusInfo
 )
 
 // DefaultSpinnerCharSeq returns the default character sequence of a spinner.
@@ -34,9 +62,64 @@ type SpinnerFormatter interface {
 	CharSeq() []string
 }
 
+// SpinnerStatusFormatter an optional extension of SpinnerFormatter for customizing the symbol that replaces the
+// indicator when a spinner is stopped with a status.
+type SpinnerStatusFormatter interface {
+	// FormatStatusIndicator returns the status symbol along with optional styling codes.
+	FormatStatusIndicator(status SpinnerStatus) string
+}
+
+// SpinnerFrameDurationFormatter an optional extension of SpinnerFormatter for animations that hold some frames longer
+// than others.
+type SpinnerFrameDurationFormatter interface {
+	// FrameDurations returns how long each of the frames returned by CharSeq is displayed, by index. Frames without a
+	// positive duration are displayed for the interval of the spinner.
+	FrameDurations() []time.Duration
+}
+
+// SpinnerProgressFormatter an optional extension of SpinnerFormatter for customizing the progress display.
+type SpinnerProgressFormatter interface {
+	// FormatProgress returns the text that represents the specified progress, where total is non-negative.
+	FormatProgress(current, total int64) string
+}
+
+// SpinnerDeadlineFormatter an optional extension of SpinnerFormatter for customizing the deadline countdown.
+type SpinnerDeadlineFormatter interface {
+	// FormatRemaining returns the time remaining until the deadline, where budget is the time the spinner had between
+	// its start and the deadline.
+	FormatRemaining(remaining, budget time.Duration) string
+}
+
+// SpinnerElapsedFormatter an optional extension of SpinnerFormatter for customizing the elapsed time display.
+type SpinnerElapsedFormatter interface {
+	// FormatElapsed returns the elapsed time with optional styling codes.
+	FormatElapsed(elapsed time.Duration) string
+}
+
+// DefaultSpinnerStatusSymbols returns the default symbols that replace the indicator of a stopped spinner.
+func DefaultSpinnerStatusSymbols() map[SpinnerStatus]string {
+	return map[SpinnerStatus]string{
+		SpinnerStatusSuccess: "✔",
+		SpinnerStatusFailure: "✖",
+		SpinnerStatusWarning: "⚠",
+		SpinnerStatusInfo:    "ℹ",
+	}
+}
+
 // SimpleSpinnerFormatter a simple spinner formatter implementation that uses the default
 // spinner character sequence and passes the title and the indicator setrings unchanged.
-type SimpleSpinnerFormatter struct{}
+type SimpleSpinnerFormatter struct {
+	// Frames an optional character sequence to use instead of the default one
+	Frames []string
+	// Durations optional per-frame durations, see SpinnerFrameDurationFormatter
+	Durations []time.Duration
+	// ProgressCounter whether progress is displayed as an n/m counter rather than a percentage
+	ProgressCounter bool
+	// DeadlineWarning the remaining time under which the countdown turns red. Defaults to a fifth of the time budget.
+	DeadlineWarning time.Duration
+	// StatusSymbols optional symbols to use instead of DefaultSpinnerStatusSymbols
+	StatusSymbols map[SpinnerStatus]string
+}
 
 // FormatTitle returns the input title as is
 func (f *SimpleSpinnerFormatter) FormatTitle(s string) string {
@@ -48,69 +131,228 @@ func (f *SimpleSpinnerFormatter) FormatIndicator(char string) string {
 	return char
 }
 
-// CharSeq returns the default character sequence.
+// CharSeq returns the configured character sequence or the default one.
 func (f *SimpleSpinnerFormatter) CharSeq() []string {
+	if len(f.Frames) > 0 {
+		return f.Frames
+	}
+
 	return DefaultSpinnerCharSeq()
 }
 
+// FrameDurations returns the configured per-frame durations
+func (f *SimpleSpinnerFormatter) FrameDurations() []time.Duration {
+	return f.Durations
+}
+
+// FormatProgress returns the progress as a percentage, or as an n/m counter if ProgressCounter is set
+func (f *SimpleSpinnerFormatter) FormatProgress(current, total int64) string {
+	if f.ProgressCounter {
+		return fmt.Sprintf("%d/%d", current, total)
+	}
+
+	percent := int64(100)
+	if total > 0 {
+		percent = max(0, min(100, current*100/total))
+	}
+
+	return fmt.Sprintf("%d%%", percent)
+}
+
+// FormatRemaining returns the time remaining until the deadline, in red once it is under DeadlineWarning
+func (f *SimpleSpinnerFormatter) FormatRemaining(remaining, budget time.Duration) string {
+	warning := f.DeadlineWarning
+	if warning <= 0 {
+		warning = budget / 5
+	}
+
+	text := "timeout in " + formatDuration(remaining)
+	if remaining <= warning {
+		return color.RedString(text)
+	}
+
+	return text
+}
+
+// FormatElapsed returns the elapsed time in a compact form, such as 1m12s
+func (f *SimpleSpinnerFormatter) FormatElapsed(elapsed time.Duration) string {
+	return formatDuration(elapsed)
+}
+
+// FormatStatusIndicator returns the symbol of the specified status in the color of the status.
+func (f *SimpleSpinnerFormatter) FormatStatusIndicator(status SpinnerStatus) string {
+	symbol, ok := f.StatusSymbols[status]
+	if !ok {
+		symbol = DefaultSpinnerStatusSymbols()[status]
+	}
+
+	switch status {
+	case SpinnerStatusSuccess:
+		return color.GreenString(symbol)
+	case SpinnerStatusFailure:
+		return color.RedString(symbol)
+	case SpinnerStatusWarning:
+		return color.YellowString(symbol)
+	default:
+		return color.BlueString(symbol)
+	}
+}
+
 // Spinner a spinning progress indicator
 type Spinner interface {
 	Start(context.Context) error
 	Stop(ctx context.Context, message string) error
+
+	// SetTitle updates the text displayed after the indicator. The title of a spinner that hasn't been started yet
+	// becomes its initial title. Returns ErrSpinnerNotActive once the spinner has stopped.
 	SetTitle(title string) error
+
+	// SetPrefix updates the text displayed before the indicator, in the same manner as SetTitle.
+	SetPrefix(prefix string) error
+
+	// SetSuffix updates the text displayed after the title, in the same manner as SetTitle.
+	SetSuffix(suffix string) error
+
+	// Println prints the operands as a permanent line above the spinner, formatted like fmt.Println, and redraws the
+	// spinner line below it. Safe for concurrent use.
+	Println(a ...any) error
+
+	// Writer returns a writer that prints complete lines written to it above the spinner, in the same manner as
+	// Println. Incomplete lines are held until they are terminated.
+	Writer() io.Writer
+
+	// SetProgress displays the specified progress after the title, once the size of the task becomes known.
+	// A negative total clears the progress. Returns ErrSpinnerNotActive once the spinner has stopped.
+	SetProgress(current, total int64) error
+
+	// Succeed stops the spinner and replaces its indicator with a success symbol.
+	// The title is kept unless a non-empty message is specified.
+	Succeed(message string) error
+
+	// Fail stops the spinner and replaces its indicator with a failure symbol.
+	// The title is kept unless a non-empty message is specified.
+	Fail(message string) error
+
+	// Warn stops the spinner and replaces its indicator with a warning symbol.
+	// The title is kept unless a non-empty message is specified.
+	Warn(message string) error
+
+	// Info stops the spinner and replaces its indicator with an information symbol.
+	// The title is kept unless a non-empty message is specified.
+	Info(message string) error
+
+	// Elapsed returns the time elapsed since the spinner was started, or the total time it ran once stopped.
+	Elapsed() time.Duration
+
+	// Pause clears the spinner line and suspends rendering until Resume is called.
+	// Title changes that happen while paused are displayed once resumed.
+	Pause() error
+
+	// Resume redraws a paused spinner and resumes rendering from the frame it was paused at.
+	Resume() error
+
+	// Run starts the spinner with the specified title, runs fn and stops the spinner with a success status, or with a
+	// failure status that includes the error fn returned. The context is passed to fn as is and its error is returned.
+	// If fn panics, the spinner is stopped with a failure status before the panic is propagated.
+	Run(ctx context.Context, title string, fn func(ctx context.Context) error) error
 }
 
 // SpinnerBuilder follows the builder pattern for creating a Spinner.
 type SpinnerBuilder interface {
 	WithWriter(writer io.Writer) SpinnerBuilder
 	WithTitle(title string) SpinnerBuilder
+	// WithInterval sets the interval between two frames. A non-positive interval falls back to the default interval.
 	WithInterval(interval time.Duration) SpinnerBuilder
 	WithFormatter(formatter SpinnerFormatter) SpinnerBuilder
+	// WithPreset sets the formatter and the interval of the spinner from the specified preset.
+	WithPreset(preset SpinnerPreset) SpinnerBuilder
+	// WithElapsedTime sets whether or not the time elapsed since the spinner was started is displayed after the title.
+	WithElapsedTime(show bool) SpinnerBuilder
+	// WithDeadlineCountdown sets whether or not the time remaining until the deadline of the context the spinner is
+	// started with is displayed after the title.
+	WithDeadlineCountdown(show bool) SpinnerBuilder
 	Build() Spinner
 }
 
 type spinner struct {
-	writer    io.Writer
-	interval  time.Duration
-	stateMx   *sync.RWMutex
-	active    bool
-	stopC     chan bool
-	titleC    chan string
-	title     string
-	formatter SpinnerFormatter
+	writer        io.Writer
+	interval      time.Duration
+	stateMx       *sync.RWMutex
+	active        bool
+	stopC         chan bool
+	pauseC        chan spinnerPauseRequest
+	doneC         chan struct{}
+	refreshC      chan struct{}
+	textMx        *sync.RWMutex
+	prefix        string
+	title         string
+	suffix        string
+	progress      *spinnerProgress
+	writeMx       *sync.Mutex
+	lastLine      string
+	permanent     bool
+	formatter     SpinnerFormatter
+	showElapsed   bool
+	showCountdown bool
+	now           func() time.Time
+	startTime     time.Time
+	stopTime      time.Time
+}
+
+// spinnerProgress the determinate progress of a spinner.
+type spinnerProgress struct {
+	current int64
+	total   int64
 }
 
-// NewSpinner creates a new Spinner with the specified update interval
+// spinnerPauseRequest asks the spinner routine to pause or resume, and is acknowledged by closing done.
+type spinnerPauseRequest struct {
+	pause bool
+	done  chan struct{}
+}
+
+// NewSpinner creates a new Spinner with the specified update interval. A non-positive interval falls back to the interval
+// of NewDefaultSpinner.
 func NewSpinner(writer io.Writer, title string, interval time.Duration, formatter SpinnerFormatter) Spinner {
+	if interval <= 0 {
+		interval = defaultSpinnerInterval
+	}
+
 	return &spinner{
 		writer:    writer,
 		interval:  interval,
 		stateMx:   &sync.RWMutex{},
 		active:    false,
 		stopC:     make(chan bool),
-		titleC:    make(chan string),
+		pauseC:    make(chan spinnerPauseRequest),
+		refreshC:  make(chan struct{}, 1),
+		textMx:    &sync.RWMutex{},
+		writeMx:   &sync.Mutex{},
 		title:     title,
 		formatter: formatter,
+		now:       time.Now,
 	}
 }
 
 // NewDefaultSpinner creates a new Spinner that writes to Stdout with a default update interval
 func NewDefaultSpinner() Spinner {
-	return NewSpinner(StdoutWriter, "", time.Millisecond*100, DefaultSpinnerFormatter())
+	return NewSpinner(StdoutWriter, "", defaultSpinnerInterval, DefaultSpinnerFormatter())
 }
 
 type spinnerBuilder struct {
-	writer    io.Writer
-	title     string
-	interval  time.Duration
-	formatter SpinnerFormatter
+	writer        io.Writer
+	title         string
+	interval      time.Duration
+	formatter     SpinnerFormatter
+	showElapsed   bool
+	showCountdown bool
 }
 
 // NewSpinnerBuilder creates a new SpinnerBuilder with default values.
 func NewSpinnerBuilder() SpinnerBuilder {
 	return &spinnerBuilder{
 		writer:    StdoutWriter,
-		interval:  time.Millisecond * 100,
+		interval:  defaultSpinnerInterval,
 		formatter: DefaultSpinnerFormatter(),
 	}
 }
@@ -135,72 +377,143 @@ func (b *spinnerBuilder) WithFormatter(formatter SpinnerFormatter) SpinnerBuilde
 	return b
 }
 
+func (b *spinnerBuilder) WithPreset(preset SpinnerPreset) SpinnerBuilder {
+	b.formatter = preset.Formatter()
+	b.interval = preset.Interval
+	return b
+}
+
+func (b *spinnerBuilder) WithElapsedTime(show bool) SpinnerBuilder {
+	b.showElapsed = show
+	return b
+}
+
+func (b *spinnerBuilder) WithDeadlineCountdown(show bool) SpinnerBuilder {
+	b.showCountdown = show
+	return b
+}
+
 func (b *spinnerBuilder) Build() Spinner {
-	return NewSpinner(b.writer, b.title, b.interval, b.formatter)
+	s := NewSpinner(b.writer, b.title, b.interval, b.formatter).(*spinner)
+	s.showElapsed = b.showElapsed
+	s.showCountdown = b.showCountdown
+
+	return s
 }
 
 func (s *spinner) writeString(str string) (n int, err error) {
 	return io.WriteString(s.writer, str)
 }
 
-// Start starts the spinner in the background.
+// writeLine replaces the spinner line with the specified line. A line that is kept is redrawn after printing above the
+// spinner, otherwise a non-empty line is permanent and is not erased by printing.
+func (s *spinner) writeLine(line string, keep bool) {
+	s.writeMx.Lock()
+	defer s.writeMx.Unlock()
+
+	s.lastLine = ""
+	if keep {
+		s.lastLine = line
+	}
+	s.permanent = !keep && line != ""
+	_, _ = s.writeString(TermControlEraseLine + line)
+}
+
+// Start starts the spinner in the background. A stopped spinner can be started again.
+// If the context ends because its deadline is exceeded, the spinner line is replaced by a timeout message.
 func (s *spinner) Start(ctx context.Context) (err error) {
+	deadline, _ := ctx.Deadline()
+	return s.start(ctx, nil, deadline)
+}
+
+// start starts the spinner in the background, optionally replacing its title before the spinner routine starts.
+// A non-zero deadline is counted down if the countdown is enabled.
+func (s *spinner) start(ctx context.Context, title *string, deadline time.Time) (err error) {
 	s.stateMx.Lock()
 	defer s.stateMx.Unlock()
 
 	if s.active {
-		return errors.New("spinner already active")
+		return ErrSpinnerAlreadyActive
 	}
 
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
 
+	if title != nil {
+		s.setText(&s.title, *title)
+	}
+
+	if s.doneC != nil {
+		// the channels of the previous run are abandoned
+		s.stopC = make(chan bool)
+		s.pauseC = make(chan spinnerPauseRequest)
+	}
 	s.active = true
+	s.startTime = s.now()
+	s.doneC = make(chan struct{})
+	startTime, stopC, pauseC, doneC := s.startTime, s.stopC, s.pauseC, s.doneC
 	waitStart := &sync.WaitGroup{}
 	waitStart.Add(1)
 
 	go func() {
 		var spinring = s.createSpinnerRing()
-		timer := time.NewTicker(s.interval)
+		timer := time.NewTimer(spinring.Value.(spinnerFrame).duration)
+		paused := false
 
 		waitStart.Done()
 
-		defer s.setActiveSafe(false)
+		defer func() {
+			// doneC is closed first, to release a concurrent stop that holds stateMx while waiting for this routine
+			close(doneC)
+			s.deactivate(doneC)
+		}()
 
-		update := func(title string) {
-			indicatorValue := s.formatter.FormatIndicator(fmt.Sprintf("%v", spinring.Value))
-			if title != "" {
-				_, _ = s.writeString(fmt.Sprintf("%s%s %s", TermControlEraseLine, indicatorValue, s.formatter.FormatTitle(title)))
-			} else {
-				_, _ = s.writeString(fmt.Sprintf("%s%s", TermControlEraseLine, indicatorValue))
+		update := func() {
+			if paused {
+				return
 			}
+			indicatorValue := s.formatter.FormatIndicator(spinring.Value.(spinnerFrame).char)
+			_, title, _ := s.text()
+			now := s.now()
+			countdown := s.renderCountdown(startTime, deadline, now)
+			s.writeLine(s.renderLine(indicatorValue, title, now.Sub(startTime), countdown), true)
 		}
 
 		for {
 			select {
 			case <-ctx.Done():
 				timer.Stop()
-				close(s.titleC)
 
-				s.printExitMessage("Cancelled...")
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					s.printExitMessage("Timed out...")
+				} else {
+					s.printExitMessage("Cancelled...")
+				}
 
 				return
 
-			case <-s.stopC:
+			case <-stopC:
 				timer.Stop()
-				close(s.titleC)
 				return
 
-			case title := <-s.titleC:
-				// The title is only written by this routine, so we're safe.
-				s.title = title
-				update(title)
+			case <-s.refreshC:
+				update()
+
+			case request := <-pauseC:
+				if request.pause && !paused {
+					s.writeLine("", false)
+				}
+				paused = request.pause
+				update()
+				close(request.done)
 
 			case <-timer.C:
-				spinring = spinring.Next()
-				title := s.title
-				update(title)
+				if !paused {
+					spinring = spinring.Next()
+				}
+				update()
+				timer.Reset(spinring.Value.(spinnerFrame).duration)
 			}
 		}
 	}()
@@ -212,16 +525,172 @@ func (s *spinner) Start(ctx context.Context) (err error) {
 
 // Stop stops the spinner and displays the specified message
 func (s *spinner) Stop(ctx context.Context, message string) (err error) {
+	return s.stop(ctx, func() string { return message })
+}
+
+// Succeed stops the spinner with a success status
+func (s *spinner) Succeed(message string) error {
+	return s.stopWithStatus(SpinnerStatusSuccess, message)
+}
+
+// Fail stops the spinner with a failure status
+func (s *spinner) Fail(message string) error {
+	return s.stopWithStatus(SpinnerStatusFailure, message)
+}
+
+// Warn stops the spinner with a warning status
+func (s *spinner) Warn(message string) error {
+	return s.stopWithStatus(SpinnerStatusWarning, message)
+}
+
+// Info stops the spinner with an information status
+func (s *spinner) Info(message string) error {
+	return s.stopWithStatus(SpinnerStatusInfo, message)
+}
+
+func (s *spinner) stopWithStatus(status SpinnerStatus, message string) error {
+	return s.stopWithStatusFn(status, func(title string) string {
+		if message == "" {
+			return title
+		}
+
+		return message
+	})
+}
+
+// stopWithStatusFn stops the spinner with the specified status and the text returned by textFn for the final title.
+func (s *spinner) stopWithStatusFn(status SpinnerStatus, textFn func(title string) string) error {
+	return s.stop(context.Background(), func() string {
+		_, title, _ := s.text()
+		text := textFn(title)
+
+		indicator := DefaultSpinnerStatusSymbols()[status]
+		if formatter, ok := s.formatter.(SpinnerStatusFormatter); ok {
+			indicator = formatter.FormatStatusIndicator(status)
+		}
+
+		return s.renderLine(indicator, text, s.stopTime.Sub(s.startTime), "")
+	})
+}
+
+// Run runs fn under the spinner and stops it with a status that reflects the outcome.
+func (s *spinner) Run(ctx context.Context, title string, fn func(ctx context.Context) error) (err error) {
+	// the spinner outlives the cancellation of ctx, so that it can report the outcome of fn
+	spinnerCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	if err = s.start(spinnerCtx, &title, deadline); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = s.stopWithStatusFn(SpinnerStatusFailure, func(title string) string {
+				return appendFailure(title, fmt.Sprintf("panic: %v", r))
+			})
+			panic(r)
+		}
+	}()
+
+	if err = fn(ctx); err != nil {
+		_ = s.stopWithStatusFn(SpinnerStatusFailure, func(title string) string {
+			return appendFailure(title, err.Error())
+		})
+	} else {
+		_ = s.Succeed("")
+	}
+
+	return err
+}
+
+// appendFailure returns the title followed by the failure description.
+func appendFailure(title, failure string) string {
+	if title == "" {
+		return failure
+	}
+
+	return title + ": " + failure
+}
+
+// renderLine returns the prefix, the indicator, the title, the progress if known and the suffix, followed by the
+// elapsed time if enabled and the countdown, if any.
+func (s *spinner) renderLine(indicator, title string, elapsed time.Duration, countdown string) string {
+	prefix, _, suffix := s.text()
+	line := indicator
+	if prefix != "" {
+		line = prefix + " " + line
+	}
+	if title != "" {
+		line += " " + s.formatter.FormatTitle(title)
+	}
+	if progress := s.renderProgress(); progress != "" {
+		line += " " + progress
+	}
+	if suffix != "" {
+		line += " " + suffix
+	}
+	if s.showElapsed {
+		formatted := formatDuration(elapsed)
+		if formatter, ok := s.formatter.(SpinnerElapsedFormatter); ok {
+			formatted = formatter.FormatElapsed(elapsed)
+		}
+		line += " " + formatted
+	}
+	if countdown != "" {
+		line += " " + countdown
+	}
+
+	return line
+}
+
+// renderCountdown returns the formatted time remaining until the deadline, or an empty string if the countdown is
+// disabled or there is no deadline.
+func (s *spinner) renderCountdown(startTime, deadline, now time.Time) string {
+	if !s.showCountdown || deadline.IsZero() {
+		return ""
+	}
+
+	formatter, ok := s.formatter.(SpinnerDeadlineFormatter)
+	if !ok {
+		formatter = &SimpleSpinnerFormatter{}
+	}
+
+	return formatter.FormatRemaining(max(0, deadline.Sub(now)), deadline.Sub(startTime))
+}
+
+// Elapsed returns the time elapsed since the spinner was started, or the total time it ran once stopped.
+func (s *spinner) Elapsed() time.Duration {
+	s.stateMx.RLock()
+	defer s.stateMx.RUnlock()
+
+	switch {
+	case s.startTime.IsZero():
+		return 0
+	case s.active:
+		return s.now().Sub(s.startTime)
+	default:
+		return s.stopTime.Sub(s.startTime)
+	}
+}
+
+// stop stops the spinner and displays the message returned by exitMessage, which is called once the spinner routine
+// has exited.
+func (s *spinner) stop(ctx context.Context, exitMessage func() string) (err error) {
 	s.stateMx.Lock()
 	defer s.stateMx.Unlock()
 
 	if !s.active {
-		err = errors.New("spinner not active")
+		err = ErrSpinnerNotActive
 	} else {
 		select {
 		case s.stopC <- true:
 			s.active = false
-			s.printExitMessage(message)
+			s.stopTime = s.now()
+			s.printExitMessage(exitMessage())
+		case <-s.doneC:
+			// the routine exited on its own, after its context was cancelled
+			err = ErrSpinnerNotActive
 		case <-ctx.Done():
 			err = ctx.Err()
 		}
@@ -231,37 +700,215 @@ func (s *spinner) Stop(ctx context.Context, message string) (err error) {
 }
 
 // SetTitle updates the spinner text.
-func (s *spinner) SetTitle(title string) (err error) {
-	defer func() {
-		if recover() != nil {
-			err = errors.New("spinner not active")
+func (s *spinner) SetTitle(title string) error {
+	return s.updateText(&s.title, title)
+}
+
+// SetPrefix updates the text before the indicator.
+func (s *spinner) SetPrefix(prefix string) error {
+	return s.updateText(&s.prefix, prefix)
+}
+
+// SetSuffix updates the text after the title.
+func (s *spinner) SetSuffix(suffix string) error {
+	return s.updateText(&s.suffix, suffix)
+}
+
+// SetProgress sets the determinate progress of the spinner.
+func (s *spinner) SetProgress(current, total int64) error {
+	return s.update(func() {
+		if total < 0 {
+			s.progress = nil
+		} else {
+			s.progress = &spinnerProgress{current: current, total: total}
 		}
-	}()
+	})
+}
 
-	s.titleC <- strings.TrimSpace(title)
+// renderProgress returns the formatted progress or an empty string if the progress is unknown.
+func (s *spinner) renderProgress() string {
+	s.textMx.RLock()
+	progress := s.progress
+	s.textMx.RUnlock()
 
-	return err
+	if progress == nil {
+		return ""
+	}
+
+	formatter, ok := s.formatter.(SpinnerProgressFormatter)
+	if !ok {
+		formatter = &SimpleSpinnerFormatter{}
+	}
+
+	return formatter.FormatProgress(progress.current, progress.total)
 }
 
+// updateText sets the specified text field in the same manner as update.
+func (s *spinner) updateText(field *string, value string) error {
+	return s.update(func() {
+		*field = strings.TrimSpace(value)
+	})
+}
+
+// update applies the specified change to the displayed state while holding textMx, unless the spinner has stopped,
+// and has a running spinner redraw it without waiting for the next frame.
+func (s *spinner) update(change func()) error {
+	s.stateMx.RLock()
+	stopped := !s.active && s.doneC != nil
+	s.stateMx.RUnlock()
+
+	if stopped {
+		return ErrSpinnerNotActive
+	}
+
+	s.textMx.Lock()
+	change()
+	s.textMx.Unlock()
+
+	select {
+	case s.refreshC <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (s *spinner) setText(field *string, value string) {
+	s.textMx.Lock()
+	defer s.textMx.Unlock()
+
+	*field = strings.TrimSpace(value)
+}
+
+// text returns the prefix, the title and the suffix of the spinner.
+func (s *spinner) text() (prefix, title, suffix string) {
+	s.textMx.RLock()
+	defer s.textMx.RUnlock()
+
+	return s.prefix, s.title, s.suffix
+}
+
+// Pause clears the spinner line and suspends rendering.
+func (s *spinner) Pause() error {
+	return s.setPaused(true)
+}
+
+// Resume redraws the spinner and resumes rendering.
+func (s *spinner) Resume() error {
+	return s.setPaused(false)
+}
+
+func (s *spinner) setPaused(pause bool) error {
+	s.stateMx.RLock()
+	active, pauseC, doneC := s.active, s.pauseC, s.doneC
+	s.stateMx.RUnlock()
+
+	if !active {
+		return ErrSpinnerNotActive
+	}
+
+	request := spinnerPauseRequest{pause: pause, done: make(chan struct{})}
+	select {
+	case pauseC <- request:
+		<-request.done
+		return nil
+	case <-doneC:
+		return ErrSpinnerNotActive
+	}
+}
+
+// printExitMessage replaces the spinner line with the specified message using a single write, so that writers that
+// hold one line at a time, such as MatrixRow, never display an empty line in between.
 func (s *spinner) printExitMessage(message string) {
-	_, _ = s.writeString(TermControlEraseLine)
-	_, _ = s.writeString(message)
+	s.writeLine(message, false)
+}
+
+// Println prints a line above the spinner.
+func (s *spinner) Println(a ...any) error {
+	return s.printLines(strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
+
+// Writer returns a writer that prints lines above the spinner.
+func (s *spinner) Writer() io.Writer {
+	return &spinnerLineWriter{spinner: s}
+}
+
+// printLines replaces the spinner line with the specified text followed by a line feed, and redraws the spinner line
+// below it using a single write. A permanent line, such as a stop message, is kept above the text.
+func (s *spinner) printLines(text string) error {
+	s.writeMx.Lock()
+	defer s.writeMx.Unlock()
+
+	lineStart := TermControlEraseLine
+	if s.permanent {
+		lineStart = TermControlCRLF
+		s.permanent = false
+	}
+	_, err := s.writeString(lineStart + text + TermControlCRLF + s.lastLine)
+
+	return err
+}
+
+// spinnerLineWriter an io.Writer that prints complete lines above a spinner.
+type spinnerLineWriter struct {
+	spinner *spinner
+	mx      sync.Mutex
+	pending []byte
+}
+
+func (w *spinnerLineWriter) Write(p []byte) (n int, err error) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	w.pending = append(w.pending, p...)
+	end := bytes.LastIndexByte(w.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+
+	lines := strings.ReplaceAll(strings.TrimRight(string(w.pending[:end]), "\r"), "\r\n", "\n")
+	w.pending = append(w.pending[:0], w.pending[end+1:]...)
+	if err = w.spinner.printLines(strings.ReplaceAll(lines, "\n", TermControlCRLF)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// spinnerFrame a single frame of the spinner animation along with how long it is displayed.
+type spinnerFrame struct {
+	char     string
+	duration time.Duration
 }
 
 func (s *spinner) createSpinnerRing() *ring.Ring {
+	var durations []time.Duration
+	if formatter, ok := s.formatter.(SpinnerFrameDurationFormatter); ok {
+		durations = formatter.FrameDurations()
+	}
+
 	r := ring.New(len(s.formatter.CharSeq()))
 
-	for _, ch := range s.formatter.CharSeq() {
-		r.Value = ch
+	for i, ch := range s.formatter.CharSeq() {
+		frame := spinnerFrame{char: ch, duration: s.interval}
+		if i < len(durations) && durations[i] > 0 {
+			frame.duration = durations[i]
+		}
+		r.Value = frame
 		r = r.Next()
 	}
 
 	return r
 }
 
-func (s *spinner) setActiveSafe(active bool) {
+// deactivate marks the spinner inactive once the routine of the run identified by doneC exits, unless it has already
+// been stopped or restarted.
+func (s *spinner) deactivate(doneC chan struct{}) {
 	s.stateMx.Lock()
 	defer s.stateMx.Unlock()
 
-	s.active = active
+	if s.active && s.doneC == doneC {
+		s.active = false
+		s.stopTime = s.now()
+	}
 }
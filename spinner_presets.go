@@ -0,0 +1,149 @@
+package termite
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// SpinnerPresetDots the default braille dots spinner
+	SpinnerPresetDots = "dots"
+	// SpinnerPresetDots2 a braille spinner with a single missing dot
+	SpinnerPresetDots2 = "dots2"
+	// SpinnerPresetDots3 a braille spinner with a wiggling tail
+	SpinnerPresetDots3 = "dots3"
+	// SpinnerPresetLine an ASCII rotating line
+	SpinnerPresetLine = "line"
+	// SpinnerPresetPipe a box drawing rotating pipe
+	SpinnerPresetPipe = "pipe"
+	// SpinnerPresetSimpleDots ASCII dots that fill up one by one
+	SpinnerPresetSimpleDots = "simple-dots"
+	// SpinnerPresetArc a rotating arc
+	SpinnerPresetArc = "arc"
+	// SpinnerPresetCircleHalves a circle that rotates its filled half
+	SpinnerPresetCircleHalves = "circle-halves"
+	// SpinnerPresetArrows a rotating arrow
+	SpinnerPresetArrows = "arrows"
+	// SpinnerPresetBouncingBar an ASCII bar bouncing between brackets
+	SpinnerPresetBouncingBar = "bouncing-bar"
+	// SpinnerPresetBlocks a block that shrinks and grows vertically
+	SpinnerPresetBlocks = "blocks"
+	// SpinnerPresetClock a clock hand going around
+	SpinnerPresetClock = "clock"
+	// SpinnerPresetMoon the phases of the moon
+	SpinnerPresetMoon = "moon"
+	// SpinnerPresetHeartbeat a heart that beats twice and rests
+	SpinnerPresetHeartbeat = "heartbeat"
+	// SpinnerPresetThinking ASCII dots that fill up and pause before starting over
+	SpinnerPresetThinking = "thinking"
+)
+
+// SpinnerPreset a named spinner character sequence along with the frame interval it looks best with.
+type SpinnerPreset struct {
+	// Name the name the preset is looked up by
+	Name string
+	// Frames the character sequence of the spinner
+	Frames []string
+	// Interval the recommended interval between two frames
+	Interval time.Duration
+	// Durations optional per-frame durations that override Interval, by frame index
+	Durations []time.Duration
+	// Unicode whether or not the frames require a terminal that supports Unicode
+	Unicode bool
+}
+
+var spinnerPresets = []SpinnerPreset{
+	{Name: SpinnerPresetDots, Frames: DefaultSpinnerCharSeq(), Interval: 80 * time.Millisecond, Unicode: true},
+	{Name: SpinnerPresetDots2, Frames: []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}, Interval: 80 * time.Millisecond, Unicode: true},
+	{Name: SpinnerPresetDots3, Frames: []string{"⠋", "⠙", "⠚", "⠞", "⠖", "⠦", "⠴", "⠲", "⠳", "⠓"}, Interval: 80 * time.Millisecond, Unicode: true},
+	{Name: SpinnerPresetLine, Frames: []string{"-", "\\", "|", "/"}, Interval: 130 * time.Millisecond},
+	{Name: SpinnerPresetPipe, Frames: []string{"┤", "┘", "┴", "└", "├", "┌", "┬", "┐"}, Interval: 100 * time.Millisecond, Unicode: true},
+	{Name: SpinnerPresetSimpleDots, Frames: []string{".  ", ".. ", "...", "   "}, Interval: 400 * time.Millisecond},
+	{Name: SpinnerPresetArc, Frames: []string{"◜", "◠", "◝", "◞", "◡", "◟"}, Interval: 100 * time.Millisecond, Unicode: true},
+	{Name: SpinnerPresetCircleHalves, Frames: []string{"◐", "◓", "◑", "◒"}, Interval: 50 * time.Millisecond, Unicode: true},
+	{Name: SpinnerPresetArrows, Frames: []string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}, Interval: 100 * time.Millisecond, Unicode: true},
+	{
+		Name: SpinnerPresetBouncingBar,
+		Frames: []string{
+			"[    ]", "[=   ]", "[==  ]", "[=== ]", "[ ===]", "[  ==]", "[   =]",
+			"[    ]", "[   =]", "[  ==]", "[ ===]", "[====]", "[=== ]", "[==  ]", "[=   ]",
+		},
+		Interval: 80 * time.Millisecond,
+	},
+	{Name: SpinnerPresetBlocks, Frames: []string{"█", "▇", "▆", "▅", "▄", "▃", "▂", "▁"}, Interval: 120 * time.Millisecond, Unicode: true},
+	{
+		Name:     SpinnerPresetClock,
+		Frames:   []string{"🕛", "🕐", "🕑", "🕒", "🕓", "🕔", "🕕", "🕖", "🕗", "🕘", "🕙", "🕚"},
+		Interval: 100 * time.Millisecond,
+		Unicode:  true,
+	},
+	{Name: SpinnerPresetMoon, Frames: []string{"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"}, Interval: 80 * time.Millisecond, Unicode: true},
+	{
+		Name:      SpinnerPresetHeartbeat,
+		Frames:    []string{"♥", "♡", "♥", "♡"},
+		Interval:  150 * time.Millisecond,
+		Durations: []time.Duration{150 * time.Millisecond, 150 * time.Millisecond, 150 * time.Millisecond, 750 * time.Millisecond},
+		Unicode:   true,
+	},
+	{
+		Name:      SpinnerPresetThinking,
+		Frames:    []string{"   ", ".  ", ".. ", "..."},
+		Interval:  300 * time.Millisecond,
+		Durations: []time.Duration{300 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond},
+	},
+}
+
+// SpinnerPresets returns all the built-in spinner presets.
+func SpinnerPresets() []SpinnerPreset {
+	presets := make([]SpinnerPreset, len(spinnerPresets))
+	for i, preset := range spinnerPresets {
+		presets[i] = preset.clone()
+	}
+
+	return presets
+}
+
+// SpinnerPresetByName returns the built-in preset with the specified name and whether or not it was found.
+func SpinnerPresetByName(name string) (SpinnerPreset, bool) {
+	for _, preset := range spinnerPresets {
+		if preset.Name == name {
+			return preset.clone(), true
+		}
+	}
+
+	return SpinnerPreset{}, false
+}
+
+// Fallback returns this preset if it doesn't require Unicode or unicode is set, otherwise the ASCII line preset.
+func (p SpinnerPreset) Fallback(unicode bool) SpinnerPreset {
+	if unicode || !p.Unicode {
+		return p
+	}
+
+	fallback, _ := SpinnerPresetByName(SpinnerPresetLine)
+	return fallback
+}
+
+// Formatter returns a SpinnerFormatter that uses the frames of this preset.
+func (p SpinnerPreset) Formatter() SpinnerFormatter {
+	return &SimpleSpinnerFormatter{Frames: p.Frames, Durations: p.Durations}
+}
+
+func (p SpinnerPreset) clone() SpinnerPreset {
+	p.Frames = append([]string(nil), p.Frames...)
+	p.Durations = append([]time.Duration(nil), p.Durations...)
+	return p
+}
+
+// UnicodeSupported returns whether or not the locale of the environment indicates a UTF-8 capable terminal.
+func UnicodeSupported() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+
+	return false
+}
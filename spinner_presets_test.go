@@ -0,0 +1,103 @@
+package termite
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinnerPresetsAreValid(t *testing.T) {
+	names := map[string]bool{}
+	for _, preset := range SpinnerPresets() {
+		assert.NotEmpty(t, preset.Name)
+		assert.False(t, names[preset.Name], "duplicate preset %s", preset.Name)
+		assert.NotEmpty(t, preset.Frames, preset.Name)
+		assert.Greater(t, preset.Interval, time.Duration(0), preset.Name)
+		assert.LessOrEqual(t, len(preset.Durations), len(preset.Frames), preset.Name)
+		assert.Equal(t, preset.Unicode, !isASCII(preset.Frames), preset.Name)
+		names[preset.Name] = true
+	}
+}
+
+func TestSpinnerPresetByName(t *testing.T) {
+	preset, ok := SpinnerPresetByName(SpinnerPresetDots)
+
+	assert.True(t, ok)
+	assert.Equal(t, DefaultSpinnerCharSeq(), preset.Frames)
+
+	_, ok = SpinnerPresetByName("no-such-preset")
+	assert.False(t, ok)
+}
+
+func TestSpinnerPresetsAreImmutable(t *testing.T) {
+	preset, _ := SpinnerPresetByName(SpinnerPresetLine)
+	preset.Frames[0] = "x"
+
+	preset, _ = SpinnerPresetByName(SpinnerPresetLine)
+	assert.Equal(t, "-", preset.Frames[0])
+}
+
+func TestSpinnerPresetFallback(t *testing.T) {
+	moon, _ := SpinnerPresetByName(SpinnerPresetMoon)
+	bouncingBar, _ := SpinnerPresetByName(SpinnerPresetBouncingBar)
+
+	assert.Equal(t, SpinnerPresetMoon, moon.Fallback(true).Name)
+	assert.Equal(t, SpinnerPresetLine, moon.Fallback(false).Name)
+	assert.Equal(t, SpinnerPresetBouncingBar, bouncingBar.Fallback(false).Name)
+}
+
+func TestSpinnerPresetFormatter(t *testing.T) {
+	preset, _ := SpinnerPresetByName(SpinnerPresetArc)
+
+	assert.Equal(t, preset.Frames, preset.Formatter().CharSeq())
+}
+
+func TestSpinnerPresetFormatterDurations(t *testing.T) {
+	preset, _ := SpinnerPresetByName(SpinnerPresetHeartbeat)
+
+	formatter, ok := preset.Formatter().(SpinnerFrameDurationFormatter)
+
+	assert.True(t, ok)
+	assert.Equal(t, preset.Durations, formatter.FrameDurations())
+}
+
+func TestSpinnerBuilderWithPreset(t *testing.T) {
+	preset, _ := SpinnerPresetByName(SpinnerPresetPipe)
+
+	spin := NewSpinnerBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithPreset(preset).
+		Build()
+
+	s := spin.(*spinner)
+	assert.Equal(t, preset.Interval, s.interval)
+	assert.Equal(t, preset.Frames, s.formatter.CharSeq())
+}
+
+func TestUnicodeSupported(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+
+	t.Setenv("LANG", "en_US.UTF-8")
+	assert.True(t, UnicodeSupported())
+
+	t.Setenv("LANG", "C")
+	assert.False(t, UnicodeSupported())
+
+	t.Setenv("LC_ALL", "C.utf8")
+	assert.True(t, UnicodeSupported())
+}
+
+func isASCII(frames []string) bool {
+	for _, frame := range frames {
+		for _, r := range frame {
+			if r > 127 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
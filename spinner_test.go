@@ -3,11 +3,17 @@ package termite
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	stdio "io"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/sha1n/gommons/pkg/io"
 	"github.com/sha1n/gommons/pkg/test"
 	"github.com/stretchr/testify/assert"
@@ -99,6 +105,47 @@ func TestSpinnerTitles(t *testing.T) {
 
 		assert.Error(t, spin.SetTitle("new title"))
 	})
+
+	t.Run("SetTitleBeforeStart", func(t *testing.T) {
+		emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+		spin := NewSpinner(emulatedStdout, "title", interval, DefaultSpinnerFormatter())
+
+		assert.NoError(t, spin.SetTitle(" initial title "))
+		assert.NoError(t, spin.Start(context.Background()))
+		defer func() { _ = spin.Stop(context.Background(), "") }()
+
+		assertBufferEventuallyContains(t, emulatedStdout, "initial title")
+	})
+
+	t.Run("SetTitleAfterCancellation", func(t *testing.T) {
+		spin := NewSpinner(new(bytes.Buffer), "title", interval, DefaultSpinnerFormatter())
+		ctx, cancel := context.WithCancel(context.Background())
+		_ = spin.Start(ctx)
+		cancel()
+
+		assert.Eventually(t, func() bool {
+			return errors.Is(spin.SetTitle("new title"), ErrSpinnerNotActive)
+		}, timeout, interval)
+	})
+}
+
+func TestSpinnerPrefixAndSuffix(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := NewSpinner(emulatedStdout, "title", interval, &SimpleSpinnerFormatter{Frames: []string{"*"}})
+
+	assert.NoError(t, spin.SetPrefix("[build]"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, spin.Start(ctx))
+	assertBufferEventuallyContains(t, emulatedStdout, "[build] * title")
+
+	assert.NoError(t, spin.SetSuffix("(3/7)"))
+	assertBufferEventuallyContains(t, emulatedStdout, "[build] * title (3/7)")
+
+	assert.NoError(t, spin.Succeed("done"))
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "[build] ✔ done (3/7)"))
+	assert.ErrorIs(t, spin.SetPrefix("x"), ErrSpinnerNotActive)
+	assert.ErrorIs(t, spin.SetSuffix("x"), ErrSpinnerNotActive)
 }
 
 func TestSpinnerErrors(t *testing.T) {
@@ -162,16 +209,487 @@ func TestSpinnerStopMessage(t *testing.T) {
 	assert.NotContains(t, emulatedStdout.String(), "\n", "line feed is expected!")
 }
 
-func assertBufferEventuallyContains(t *testing.T, outBuffer *bytes.Buffer, expected string) {
+func TestSpinnerStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		stop     func(Spinner, string) error
+		expected string
+	}{
+		{name: "Succeed", stop: Spinner.Succeed, expected: "✔"},
+		{name: "Fail", stop: Spinner.Fail, expected: "✖"},
+		{name: "Warn", stop: Spinner.Warn, expected: "⚠"},
+		{name: "Info", stop: Spinner.Info, expected: "ℹ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("KeepsTitle", func(t *testing.T) {
+				emulatedStdout := new(bytes.Buffer)
+				spin := startSpinner(t, emulatedStdout, "title", DefaultSpinnerFormatter())
+
+				assert.NoError(t, tt.stop(spin, ""))
+				assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+tt.expected+" title"))
+			})
+
+			t.Run("OverridesTitle", func(t *testing.T) {
+				emulatedStdout := new(bytes.Buffer)
+				spin := startSpinner(t, emulatedStdout, "title", DefaultSpinnerFormatter())
+
+				assert.NoError(t, tt.stop(spin, "message"))
+				assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+tt.expected+" message"))
+			})
+
+			t.Run("NotActive", func(t *testing.T) {
+				spin := NewSpinner(new(bytes.Buffer), "", interval, DefaultSpinnerFormatter())
+
+				assert.Error(t, tt.stop(spin, ""))
+			})
+		})
+	}
+}
+
+func TestSpinnerStatusCustomSymbols(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	formatter := &SimpleSpinnerFormatter{StatusSymbols: map[SpinnerStatus]string{SpinnerStatusSuccess: "OK"}}
+	spin := startSpinner(t, emulatedStdout, "", formatter)
+
+	assert.NoError(t, spin.Succeed(""))
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"OK"))
+}
+
+func TestSpinnerStatusWithoutStatusFormatter(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	spin := startSpinner(t, emulatedStdout, "title", &titleOnlySpinnerFormatter{})
+
+	assert.NoError(t, spin.Fail(""))
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"✖ <title>"))
+}
+
+func TestSpinnerStatusInMatrixRow(t *testing.T) {
+	matrix := NewMatrix(new(bytes.Buffer), time.Hour)
+	row := matrix.NewRow()
+	spin := startSpinner(t, row, "title", DefaultSpinnerFormatter())
+
+	assert.NoError(t, spin.Succeed("done"))
+	assert.Equal(t, strings.TrimLeft(TermControlEraseLine, "\r")+"✔ done", row.(*matrixRow).value)
+}
+
+func TestSpinnerBuilderWithElapsedTime(t *testing.T) {
+	spin := NewSpinnerBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithElapsedTime(true).
+		Build()
+
+	assert.True(t, spin.(*spinner).showElapsed)
+}
+
+func TestSpinnerElapsedTime(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	clock := &atomic.Int64{}
+	spin := NewSpinner(emulatedStdout, "title", interval, DefaultSpinnerFormatter()).(*spinner)
+	spin.showElapsed = true
+	spin.now = func() time.Time { return time.Unix(clock.Load(), 0) }
+
+	assert.Equal(t, time.Duration(0), spin.Elapsed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, spin.Start(ctx))
+	assertBufferEventuallyContains(t, emulatedStdout, "title 0s")
+
+	clock.Store(72)
+	assertBufferEventuallyContains(t, emulatedStdout, "title 1m12s")
+	assert.Equal(t, 72*time.Second, spin.Elapsed())
+
+	assert.NoError(t, spin.Succeed(""))
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "✔ title 1m12s"))
+
+	clock.Store(100)
+	assert.Equal(t, 72*time.Second, spin.Elapsed())
+}
+
+func TestSpinnerPauseResume(t *testing.T) {
+	probedWriter := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := startSpinner(t, probedWriter, "", DefaultSpinnerFormatter())
+
+	assert.NoError(t, spin.Pause())
+	assert.NoError(t, spin.Pause())
+	assert.True(t, strings.HasSuffix(probedWriter.String(), TermControlEraseLine))
+	assertStoppedEventually(t, probedWriter, spin.(*spinner))
+
+	assert.NoError(t, spin.Resume())
+	assertSpinnerCharSequence(t, probedWriter)
+
+	assert.NoError(t, spin.Pause())
+	assert.NoError(t, spin.SetTitle("new title"))
+	assertStoppedEventually(t, probedWriter, spin.(*spinner))
+
+	assert.NoError(t, spin.Resume())
+	assertBufferEventuallyContains(t, probedWriter, "new title")
+}
+
+func TestSpinnerPauseNotActive(t *testing.T) {
+	spin := NewSpinner(new(bytes.Buffer), "", interval, DefaultSpinnerFormatter())
+
+	assert.Error(t, spin.Pause())
+	assert.Error(t, spin.Resume())
+}
+
+func TestSpinnerRestart(t *testing.T) {
+	probedWriter := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := startSpinner(t, probedWriter, "title", DefaultSpinnerFormatter())
+	assert.NoError(t, spin.Stop(context.Background(), "stopped"))
+	assert.Error(t, spin.SetTitle("ignored"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, spin.Start(ctx))
+	assert.NoError(t, spin.SetTitle("restarted"))
+	assertBufferEventuallyContains(t, probedWriter, "restarted")
+
+	assert.NoError(t, spin.Stop(context.Background(), "stopped again"))
+	assertBufferEventuallyContains(t, probedWriter, "stopped again")
+}
+
+func TestSpinnerRestartAfterCancellation(t *testing.T) {
+	spin := NewSpinner(new(bytes.Buffer), "", interval, DefaultSpinnerFormatter())
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, spin.Start(ctx))
+	cancel()
+
+	assert.Eventually(t, func() bool { return spin.Start(context.Background()) == nil }, timeout, interval)
+	assert.NoError(t, spin.Stop(context.Background(), ""))
+}
+
+func TestSpinnerRun(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		emulatedStdout := new(bytes.Buffer)
+		spin := NewSpinner(emulatedStdout, "", interval, DefaultSpinnerFormatter())
+
+		err := spin.Run(context.Background(), "working", func(ctx context.Context) error {
+			return spin.SetTitle("almost done")
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"✔ almost done"))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		emulatedStdout := new(bytes.Buffer)
+		spin := NewSpinner(emulatedStdout, "", interval, DefaultSpinnerFormatter())
+		expectedErr := errors.New("boom")
+
+		err := spin.Run(context.Background(), "working", func(ctx context.Context) error {
+			return expectedErr
+		})
+
+		assert.Equal(t, expectedErr, err)
+		assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"✖ working: boom"))
+	})
+
+	t.Run("ContextCancellation", func(t *testing.T) {
+		emulatedStdout := new(bytes.Buffer)
+		spin := NewSpinner(emulatedStdout, "", interval, DefaultSpinnerFormatter())
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := spin.Run(ctx, "working", func(ctx context.Context) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"✖ working: context canceled"))
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		emulatedStdout := new(bytes.Buffer)
+		spin := NewSpinner(emulatedStdout, "", interval, DefaultSpinnerFormatter())
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = spin.Run(context.Background(), "working", func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+		assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"✖ working: panic: boom"))
+		assert.Error(t, spin.Stop(context.Background(), ""))
+	})
+
+	t.Run("AlreadyActive", func(t *testing.T) {
+		spin := startSpinner(t, new(bytes.Buffer), "", DefaultSpinnerFormatter())
+		called := false
+
+		err := spin.Run(context.Background(), "working", func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestSpinnerFrameDurations(t *testing.T) {
+	formatter := &SimpleSpinnerFormatter{
+		Frames:    []string{"a", "b", "c"},
+		Durations: []time.Duration{time.Second, -time.Second},
+	}
+	spin := NewSpinner(new(bytes.Buffer), "", time.Millisecond, formatter).(*spinner)
+
+	var frames []spinnerFrame
+	spin.createSpinnerRing().Do(func(value any) {
+		frames = append(frames, value.(spinnerFrame))
+	})
+
+	assert.Equal(t, []spinnerFrame{
+		{char: "a", duration: time.Second},
+		{char: "b", duration: time.Millisecond},
+		{char: "c", duration: time.Millisecond},
+	}, frames)
+}
+
+func TestSpinnerNonPositiveInterval(t *testing.T) {
+	formatter := &SimpleSpinnerFormatter{Frames: []string{"a"}}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		spin := NewSpinnerBuilder().WithInterval(interval).WithFormatter(formatter).Build().(*spinner)
+
+		assert.Equal(t, spinnerFrame{char: "a", duration: defaultSpinnerInterval}, spin.createSpinnerRing().Value)
+	}
+}
+
+func TestSpinnerFrameDurationsAnimation(t *testing.T) {
+	probedWriter := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	formatter := &SimpleSpinnerFormatter{
+		Frames:    []string{"a", "b"},
+		Durations: []time.Duration{interval, time.Hour},
+	}
+	spin := NewSpinner(probedWriter, "", interval, formatter)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, spin.Start(ctx))
+
+	assertBufferEventuallyContains(t, probedWriter, "b")
+	assertStoppedEventually(t, probedWriter, spin.(*spinner))
+}
+
+func TestSpinnerProgress(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := startSpinner(t, emulatedStdout, "resolving", &SimpleSpinnerFormatter{Frames: []string{"*"}})
+	assertBufferEventuallyContains(t, emulatedStdout, "* resolving")
+
+	assert.NoError(t, spin.SetTitle("downloading"))
+	assert.NoError(t, spin.SetSuffix("(mirror)"))
+	assert.NoError(t, spin.SetProgress(45, 100))
+	assertBufferEventuallyContains(t, emulatedStdout, "* downloading 45% (mirror)")
+
+	assert.NoError(t, spin.SetProgress(0, -1))
+	emulatedStdout.Reset()
+	assertBufferEventuallyContains(t, emulatedStdout, "* downloading (mirror)")
+
+	assert.NoError(t, spin.Succeed(""))
+	assert.ErrorIs(t, spin.SetProgress(1, 1), ErrSpinnerNotActive)
+}
+
+func TestSimpleSpinnerFormatterFormatProgress(t *testing.T) {
+	percentFormatter := &SimpleSpinnerFormatter{}
+	counterFormatter := &SimpleSpinnerFormatter{ProgressCounter: true}
+
+	assert.Equal(t, "45%", percentFormatter.FormatProgress(45, 100))
+	assert.Equal(t, "100%", percentFormatter.FormatProgress(150, 100))
+	assert.Equal(t, "100%", percentFormatter.FormatProgress(0, 0))
+	assert.Equal(t, "3/7", counterFormatter.FormatProgress(3, 7))
+}
+
+func TestSpinnerProgressWithoutProgressFormatter(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := startSpinner(t, emulatedStdout, "title", &titleOnlySpinnerFormatter{})
+
+	assert.NoError(t, spin.SetProgress(1, 4))
+	assertBufferEventuallyContains(t, emulatedStdout, "<title> 25%")
+}
+
+func TestSpinnerPrintln(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := startSpinner(t, emulatedStdout, "title", &SimpleSpinnerFormatter{Frames: []string{"*"}})
+	assertBufferEventuallyContains(t, emulatedStdout, "* title")
+
+	assert.NoError(t, spin.Println("hello", 42))
+
+	assert.Contains(t, emulatedStdout.String(), TermControlEraseLine+"hello 42"+TermControlCRLF+"* title")
+}
+
+func TestSpinnerPrintlnAfterStop(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	spin := startSpinner(t, emulatedStdout, "title", DefaultSpinnerFormatter())
+	assert.NoError(t, spin.Succeed("done"))
+
+	assert.NoError(t, spin.Println("first"))
+	assert.NoError(t, spin.Println("second"))
+
+	assert.True(t, strings.HasSuffix(
+		emulatedStdout.String(),
+		"✔ done"+TermControlCRLF+"first"+TermControlCRLF+TermControlEraseLine+"second"+TermControlCRLF,
+	))
+}
+
+func TestSpinnerWriter(t *testing.T) {
+	emulatedStdout := new(bytes.Buffer)
+	spin := NewSpinner(emulatedStdout, "", interval, DefaultSpinnerFormatter())
+	writer := spin.Writer()
+
+	n, err := writer.Write([]byte("first\r\nsec"))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, n)
+	assert.Equal(t, TermControlEraseLine+"first"+TermControlCRLF, emulatedStdout.String())
+
+	_, err = writer.Write([]byte("ond\nthird\n"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), TermControlEraseLine+"second"+TermControlCRLF+"third"+TermControlCRLF))
+}
+
+func TestSpinnerPrintlnConcurrently(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := startSpinner(t, emulatedStdout, "title", DefaultSpinnerFormatter())
+	writer := spin.Writer()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, spin.Println("println", i))
+			_, err := fmt.Fprintf(writer, "writer %d\n", i)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, spin.Stop(context.Background(), ""))
+
+	for i := 0; i < 10; i++ {
+		assert.Contains(t, emulatedStdout.String(), fmt.Sprintf("println %d%s", i, TermControlCRLF))
+		assert.Contains(t, emulatedStdout.String(), fmt.Sprintf("writer %d%s", i, TermControlCRLF))
+	}
+}
+
+func TestSpinnerBuilderWithDeadlineCountdown(t *testing.T) {
+	spin := NewSpinnerBuilder().
+		WithWriter(new(bytes.Buffer)).
+		WithDeadlineCountdown(true).
+		Build()
+
+	assert.True(t, spin.(*spinner).showCountdown)
+}
+
+func TestSpinnerDeadlineCountdown(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	base := time.Now()
+	offset := &atomic.Int64{}
+	spin := NewSpinner(emulatedStdout, "title", interval, DefaultSpinnerFormatter()).(*spinner)
+	spin.showCountdown = true
+	spin.now = func() time.Time { return base.Add(time.Duration(offset.Load())) }
+
+	ctx, cancel := context.WithDeadline(context.Background(), base.Add(100*time.Second))
+	defer cancel()
+	assert.NoError(t, spin.Start(ctx))
+	assertBufferEventuallyContains(t, emulatedStdout, "title timeout in 1m40s")
+
+	offset.Store(int64(90 * time.Second))
+	assertBufferEventuallyContains(t, emulatedStdout, "title timeout in 10s")
+
+	assert.NoError(t, spin.Succeed(""))
+	assert.True(t, strings.HasSuffix(emulatedStdout.String(), "✔ title"))
+}
+
+func TestSpinnerDeadlineExceeded(t *testing.T) {
+	emulatedStdout := io.NewUnlimitedProbedWriter(new(bytes.Buffer))
+	spin := NewSpinner(emulatedStdout, "title", interval, DefaultSpinnerFormatter())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	assert.NoError(t, spin.Start(ctx))
+
+	assertBufferEventuallyContains(t, emulatedStdout, "Timed out...")
+	assert.NotContains(t, emulatedStdout.String(), "Cancelled...")
+}
+
+func TestSpinnerStatusRacingCancellation(t *testing.T) {
+	writer := &blockingWriter{
+		blockOn:  "Cancelled...",
+		blockedC: make(chan struct{}),
+		releaseC: make(chan struct{}),
+	}
+	spin := NewSpinner(writer, "title", interval, DefaultSpinnerFormatter())
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, spin.Start(ctx))
+
+	cancel()
+	<-writer.blockedC
+
+	resultC := make(chan error)
+	go func() {
+		resultC <- spin.Succeed("ok")
+	}()
+	// give Succeed the time to block on the spinner routine before letting it exit
+	time.Sleep(time.Millisecond * 50)
+	close(writer.releaseC)
+
+	select {
+	case err := <-resultC:
+		assert.ErrorIs(t, err, ErrSpinnerNotActive)
+	case <-time.After(timeout):
+		t.Fatal("Succeed did not return after the spinner was cancelled")
+	}
+	assert.ErrorIs(t, spin.Fail("again"), ErrSpinnerNotActive)
+}
+
+func TestSimpleSpinnerFormatterFormatRemaining(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	formatter := &SimpleSpinnerFormatter{}
+	assert.Equal(t, "timeout in 12s", formatter.FormatRemaining(12*time.Second, time.Minute/2))
+	assert.Equal(t, color.RedString("timeout in 12s"), formatter.FormatRemaining(12*time.Second, time.Minute))
+
+	formatter.DeadlineWarning = 5 * time.Second
+	assert.Equal(t, "timeout in 12s", formatter.FormatRemaining(12*time.Second, time.Minute/2))
+	assert.Equal(t, color.RedString("timeout in 5s"), formatter.FormatRemaining(5*time.Second, time.Minute/2))
+}
+
+func startSpinner(t *testing.T, writer stdio.Writer, title string, formatter SpinnerFormatter) Spinner {
+	spin := NewSpinner(writer, title, interval, formatter)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	assert.NoError(t, spin.Start(ctx))
+
+	return spin
+}
+
+type titleOnlySpinnerFormatter struct{}
+
+func (f *titleOnlySpinnerFormatter) FormatTitle(s string) string {
+	return "<" + s + ">"
+}
+
+func (f *titleOnlySpinnerFormatter) FormatIndicator(char string) string {
+	return char
+}
+
+func (f *titleOnlySpinnerFormatter) CharSeq() []string {
+	return DefaultSpinnerCharSeq()
+}
+
+func assertBufferEventuallyContains(t *testing.T, outBuffer fmt.Stringer, expected string) {
 	assert.Eventually(
 		t,
 		bufferContains(outBuffer, expected),
 		timeout,
-		interval,
+		time.Millisecond,
 	)
 }
 
-func bufferContains(outBuffer *bytes.Buffer, expected string) func() bool {
+func bufferContains(outBuffer fmt.Stringer, expected string) func() bool {
 	return func() bool {
 		return strings.Contains(outBuffer.String(), expected)
 	}
@@ -213,3 +731,22 @@ func stripControlCharacters(input string) string {
 
 	return controlCharsRegex.ReplaceAllString(input, "")
 }
+
+// blockingWriter blocks the first write that contains blockOn until releaseC is closed.
+type blockingWriter struct {
+	blockOn  string
+	blockedC chan struct{}
+	releaseC chan struct{}
+	once     sync.Once
+}
+
+func (w *blockingWriter) Write(b []byte) (int, error) {
+	if strings.Contains(string(b), w.blockOn) {
+		w.once.Do(func() {
+			close(w.blockedC)
+			<-w.releaseC
+		})
+	}
+
+	return len(b), nil
+}
@@ -2,6 +2,11 @@ package termite
 
 import (
 	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
 )
 
 // TruncateString returns a string that is at most maxLen long.
@@ -15,3 +20,119 @@ func TruncateString(s string, maxLen int) string {
 	}
 	return s
 }
+
+// displayTabWidth the distance between two tab stops of a terminal
+const displayTabWidth = 8
+
+// displayWidth returns the number of terminal columns s occupies, ignoring ANSI escape sequences and control characters.
+// Tabs are expanded to the next tab stop.
+func displayWidth(s string) int {
+	column := 0
+	for i := 0; i < len(s); {
+		if n := controlSequenceLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		column += runeColumns(r, column)
+		i += size
+	}
+
+	return column
+}
+
+// controlSequenceLength returns the length in bytes of the ANSI escape sequence or control character s starts with,
+// or 0 if it starts with a displayed character. Tabs are considered displayed characters.
+func controlSequenceLength(s string) int {
+	switch {
+	case s == "":
+		return 0
+
+	case s[0] == '\x1b' && len(s) > 1 && s[1] == '[':
+		// CSI: parameter and intermediate bytes followed by a final byte
+		i := 2
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x3f {
+			i++
+		}
+		if i < len(s) && s[i] >= 0x40 && s[i] <= 0x7e {
+			i++
+		}
+		return i
+
+	case s[0] == '\x1b' && len(s) > 1 && s[1] == ']':
+		// OSC, such as a hyperlink: terminated by BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+
+	case s[0] == '\x1b':
+		return min(2, len(s))
+
+	case s[0] != '\t' && (s[0] < 0x20 || s[0] == 0x7f):
+		return 1
+	}
+
+	return 0
+}
+
+// runeColumns returns the number of terminal columns r occupies when displayed at the specified column.
+func runeColumns(r rune, column int) int {
+	if r == '\t' {
+		return displayTabWidth - column%displayTabWidth
+	}
+
+	return runeWidth(r)
+}
+
+// runeWidth returns the number of terminal columns r occupies.
+func runeWidth(r rune) int {
+	if r >= 0x20 && r < 0x7f {
+		return 1
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// sliceDisplayColumns returns the characters of s that are displayed in the specified range of terminal columns.
+// ANSI escape sequences and control characters are all kept, so that styles still apply and are reset as in s.
+// Tabs are expanded to spaces and a wide character that doesn't fit in the range entirely is dropped.
+func sliceDisplayColumns(s string, from, columns int) string {
+	var sb strings.Builder
+	column := 0
+	for i := 0; i < len(s); {
+		if n := controlSequenceLength(s[i:]); n > 0 {
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeColumns(r, column)
+		switch {
+		case r == '\t':
+			visibleFrom, visibleTo := max(column, from), min(column+w, from+columns)
+			sb.WriteString(strings.Repeat(" ", max(0, visibleTo-visibleFrom)))
+		case column >= from && column+w <= from+columns:
+			sb.WriteRune(r)
+		}
+		column += w
+		i += size
+	}
+
+	return sb.String()
+}
@@ -24,3 +24,54 @@ func TestTruncateString(t *testing.T) {
 		})
 	}
 }
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "plain", s: "hello", want: 5},
+		{name: "multi-byte runes", s: "██░", want: 3},
+		{name: "wide runes", s: "日本語", want: 6},
+		{name: "emoji", s: "🌑 moon", want: 7},
+		{name: "combining marks", s: "é", want: 1},
+		{name: "color codes", s: "\x1b[32m日本\x1b[0m", want: 4},
+		{name: "tabs", s: "ab\tc\t", want: 16},
+		{name: "hyperlink", s: "\x1b]8;;https://example.com\x07link\x1b]8;;\x1b\\", want: 4},
+		{name: "erase line", s: TermControlEraseLine + "abc", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.s); got != tt.want {
+				t.Errorf("displayWidth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSliceDisplayColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		from    int
+		columns int
+		want    string
+	}{
+		{name: "prefix", s: "hello world", from: 0, columns: 5, want: "hello"},
+		{name: "middle", s: "hello world", from: 6, columns: 3, want: "wor"},
+		{name: "wide runes", s: "日本語", from: 0, columns: 5, want: "日本"},
+		{name: "wide rune on the edge", s: "a日本", from: 2, columns: 2, want: ""},
+		{name: "keeps escape sequences", s: "\x1b[32mhello\x1b[0m world", from: 0, columns: 3, want: "\x1b[32mhel\x1b[0m"},
+		{name: "expands tabs", s: "ab\tcdefghijklmnop", from: 0, columns: 10, want: "ab      cd"},
+		{name: "tab on the edge", s: "ab\tcd", from: 0, columns: 4, want: "ab  "},
+		{name: "keeps hyperlinks", s: "\x1b]8;;https://example.com\x07link\x1b]8;;\x07", from: 0, columns: 2, want: "\x1b]8;;https://example.com\x07li\x1b]8;;\x07"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sliceDisplayColumns(tt.s, tt.from, tt.columns); got != tt.want {
+				t.Errorf("sliceDisplayColumns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}